@@ -4,8 +4,12 @@
 package simpledi
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 )
 
 var (
@@ -35,6 +39,30 @@ var (
 
 	// ErrTypeMismatch indicates that a requested instance type does not match.
 	ErrTypeMismatch = errors.New("Type mismatch")
+
+	// ErrDecoratorRequired indicates that a decorator has no function.
+	ErrDecoratorRequired = errors.New("Decorator required")
+
+	// ErrDependencyPrivate indicates that a dependency in Deps is private to another module.
+	ErrDependencyPrivate = errors.New("Dependency private")
+
+	// ErrModuleNameRequired indicates that a module has no name.
+	ErrModuleNameRequired = errors.New("Module name required")
+
+	// ErrKeyRequired indicates that a definition added to a map has no key.
+	ErrKeyRequired = errors.New("Key required")
+
+	// ErrKeyDuplicate indicates that a map already has a definition with the same key.
+	ErrKeyDuplicate = errors.New("Key duplicate")
+
+	// ErrSnapshotStale indicates that the instances of a snapshot were closed by Close.
+	ErrSnapshotStale = errors.New("Snapshot stale")
+
+	// ErrAsyncPending indicates that New of an async definition is still running.
+	ErrAsyncPending = errors.New("Async pending")
+
+	// ErrDependencyAsync indicates that an alias, group or map refers to an async definition.
+	ErrDependencyAsync = errors.New("Dependency async")
 )
 
 // Definition describes a dependency definition.
@@ -43,10 +71,53 @@ type Definition struct {
 	ID string
 	// Deps is the list of dependency IDs. Optional.
 	Deps []string
-	// New is the function that returns a new instance. Required.
+	// Optional is the list of dependency IDs that may be absent. Optional.
+	// Defined ones are resolved before the definition, like Deps.
+	Optional []string
+	// Weak is the list of IDs the instance fetches later through a Provider. Optional.
+	// They are reported by WeakGraph but do not affect the resolve order,
+	// so two definitions can refer to each other without ErrDependencyCycle.
+	Weak []string
+	// New is the function that returns a new instance. Required unless NewErr is set.
 	New func() any
+	// NewErr is the function that returns a new instance or an error. Required unless New is set.
+	// A failure is returned by Resolve like any other error.
+	NewErr func() (any, error)
+	// Retry is the policy for calling NewErr again after a failure. Optional.
+	Retry RetryPolicy
 	// Close is the function called on container close. Optional.
 	Close func() error
+	// Private restricts references in Deps to definitions of the same module. Optional.
+	Private bool
+	// When is the list of conditions evaluated at Resolve. Optional.
+	// The definition is ignored unless all conditions hold.
+	When []Condition
+	// Group is the name of a group the instance is added to. Optional.
+	// A group is a definition with the group name as ID that depends on all its members,
+	// and its instance is a []any of member instances in registration order.
+	// A group whose members are all ignored by When is kept with no members.
+	Group string
+	// Map is the name of a map the instance is added to under Key. Optional.
+	// A map is a definition with the map name as ID that depends on all its members,
+	// and its instance is a map[string]any of member instances.
+	// A map whose members are all ignored by When is kept with no members.
+	Map string
+	// Key is the key of the instance in Map. Required if Map is set.
+	Key string
+	// Async runs New in a goroutine at Resolve. Optional.
+	// The instance is a *Future until New succeeds, dependents are created without waiting for it.
+	// Aliases, groups and maps cannot refer to it.
+	// Retries stop once the context of ResolveContext is done or Close is called.
+	Async bool
+	// Reloadable marks the definition to be created again by Reload, along with its dependents. Optional.
+	Reloadable bool
+
+	seq       uint64
+	module    string
+	namespace string
+	synthetic bool
+	optional  []string
+	build     func() (any, error)
 }
 
 // Container is a simple dependency injection container.
@@ -54,16 +125,53 @@ type Definition struct {
 // A container stores definitions, resolves their dependencies,
 // creates instances, and manages cleanup.
 type Container struct {
+	mu          sync.RWMutex
+	rebuildMu   sync.Mutex
 	resolved    bool
 	definitions []Definition
 	instances   map[string]any
+	staging     map[string]any
+	closing     map[string]retiredInstance
+	builds      map[string]uint64
+	generations map[string]uint64
+	watchers    map[string]map[uint64]func(Event)
+	watchSerial uint64
+	lifetime    context.Context
+	endLifetime context.CancelFunc
+	serial      uint64
+	registered  uint64
+	epoch       uint64
+	pinned      map[uint64]bool
+	retired     []retiredInstance
+	decorators  map[string][]decorator
+	overridden  []Definition
+	ignored     []Definition
+	profiles    []string
+	stats       stats
+	observers   []Observer
+	logger      *slog.Logger
+	logLevel    slog.Level
+	trace       bool
 }
 
+// Option configures a Container.
+type Option func(c *Container)
+
 // New returns a new Container.
-func New() *Container {
-	return &Container{
-		instances: make(map[string]any),
+func New(opts ...Option) *Container {
+	c := &Container{
+		instances:   make(map[string]any),
+		builds:      make(map[string]uint64),
+		generations: make(map[string]uint64),
+		pinned:      make(map[uint64]bool),
+		decorators:  make(map[string][]decorator),
+	}
+	c.lifetime, c.endLifetime = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
 // Set adds a definition to the container.
@@ -76,14 +184,31 @@ func (c *Container) Set(d Definition) error {
 	if d.ID == "" {
 		return fmt.Errorf("%s: %w", op, ErrIDRequired)
 	}
-	if d.New == nil {
+	if d.New == nil && d.NewErr == nil {
 		return fmt.Errorf("%s: %w (ID: %s)", op, ErrNewRequired, d.ID)
 	}
-	c.definitions = append(c.definitions, d)
+	c.register(d)
 
 	return nil
 }
 
+// register appends the definitions to the container, recording their registration order.
+func (c *Container) register(ds ...Definition) {
+	c.definitions = append(c.definitions, c.sequence(ds)...)
+}
+
+// sequence returns copies of the definitions numbered in registration order.
+func (c *Container) sequence(ds []Definition) []Definition {
+	sequenced := make([]Definition, len(ds))
+	for i, d := range ds {
+		c.registered++
+		d.seq = c.registered
+		sequenced[i] = d
+	}
+
+	return sequenced
+}
+
 // Get returns an instance by ID.
 func (c *Container) Get(id string) (any, error) {
 	const op = "simpledi.Get"
@@ -91,7 +216,8 @@ func (c *Container) Get(id string) (any, error) {
 	if id == "" {
 		return nil, fmt.Errorf("%s: %w", op, ErrIDRequired)
 	}
-	instance, ok := c.instances[id]
+	c.onGet(id)
+	instance, ok := c.lookup(id)
 	if !ok {
 		return nil, fmt.Errorf("%s: %w (ID: %s)", op, ErrIDNotFound, id)
 	}
@@ -104,41 +230,143 @@ func (c *Container) Get(id string) (any, error) {
 func (c *Container) Resolve() error {
 	const op = "simpledi.Resolve"
 
+	if err := c.resolve(context.Background(), op, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResolveOnly creates instances for the given IDs and their dependencies, directly or transitively.
+// All definitions are still validated, the rest are left unbuilt.
+func (c *Container) ResolveOnly(ids ...string) error {
+	const op = "simpledi.ResolveOnly"
+
+	if len(ids) == 0 {
+		return fmt.Errorf("%s: %w", op, ErrIDRequired)
+	}
+	if err := c.resolve(context.Background(), op, ids); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResolveContext is like Resolve, but stops retrying constructors once ctx is done,
+// including constructors of Async definitions still running after it returns.
+func (c *Container) ResolveContext(ctx context.Context) error {
+	const op = "simpledi.ResolveContext"
+
+	if err := c.resolve(ctx, op, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// resolve creates instances for the given roots and their dependencies, or for all definitions if roots is nil.
+func (c *Container) resolve(ctx context.Context, op string, roots []string) error {
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
 	if c.resolved {
-		return fmt.Errorf("%s: %w", op, ErrContainerResolved)
+		return ErrContainerResolved
+	}
+	start := time.Now()
+	previous := c.definitions
+	fail := func(err error) error {
+		c.definitions = previous
+		return err
 	}
+	definitions, ignored, err := c.prepare(c.definitions)
+	if err != nil {
+		return err
+	}
+	c.definitions = definitions
+	c.ignored = ignored
 	if err := c.sort(); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return fail(err)
 	}
-	for _, definition := range c.definitions {
-		instance := definition.New()
-		c.instances[definition.ID] = instance
+	var selected map[string]bool
+	if roots != nil {
+		if selected, err = c.requirements(roots); err != nil {
+			return fail(err)
+		}
+	}
+	c.stats.reset(c.definitions, c.dependencies)
+	ctx, end := c.traceTask(ctx, op)
+	defer end()
+	for i, definition := range c.definitions {
+		if selected != nil && !selected[definition.ID] {
+			continue
+		}
+		if err := c.newInstance(ctx, definition); err != nil {
+			return fail(errors.Join(err, c.rollback(ctx, c.definitions[:i])))
+		}
 	}
 	c.resolved = true
+	c.stats.recordResolve(time.Since(start))
 
 	return nil
 }
 
 // Close calls Close for all definitions that provide it, in reverse order.
+// It stops retries of Async definitions, waits for them and skips Close of the failed ones.
 // Returns a combined error if any Close calls fail.
 // The container is then cleared and can be reused.
 func (c *Container) Close() error {
 	const op = "simpledi.Close"
 
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
 	errs := make([]error, 0)
 	if c.resolved {
+		start := time.Now()
+		ctx, end := c.traceTask(context.Background(), op)
+		c.endLifetime()
+		for _, a := range c.futures() {
+			if a.future != nil {
+				<-a.future.done
+			}
+		}
 		for i := len(c.definitions) - 1; i >= 0; i-- {
 			definition := c.definitions[i]
+			if !c.built(definition.ID) {
+				continue
+			}
 			if definition.Close != nil {
-				if err := definition.Close(); err != nil {
-					errs = append(errs, fmt.Errorf("%s: %w (ID: %s)", op, err, definition.ID))
+				if err := c.closeInstance(ctx, definition); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w (%s)", op, err, definition.label()))
 				}
 			}
+			c.notify(Event{ID: definition.ID, Kind: EventClosed, Generation: c.Generation(definition.ID)})
 		}
+		for i := len(c.retired) - 1; i >= 0; i-- {
+			retired := c.retired[i]
+			if retired.definition.Close != nil {
+				if err := c.closeSuperseded(ctx, retired); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w (%s)", op, err, retired.definition.label()))
+				}
+			}
+		}
+		end()
+		c.stats.recordCloseTotal(time.Since(start))
 	}
 
-	c.definitions = make([]Definition, 0)
+	c.mu.Lock()
 	c.instances = make(map[string]any)
+	c.builds = make(map[string]uint64)
+	c.generations = make(map[string]uint64)
+	c.lifetime, c.endLifetime = context.WithCancel(context.Background())
+	c.mu.Unlock()
+	c.definitions = make([]Definition, 0)
+	c.pinned = make(map[uint64]bool)
+	c.retired = nil
+	c.epoch++
+	c.decorators = make(map[string][]decorator)
+	c.overridden = nil
+	c.ignored = nil
 	c.resolved = false
 
 	if len(errs) > 0 {
@@ -148,20 +376,269 @@ func (c *Container) Close() error {
 	return nil
 }
 
+func (c *Container) newInstance(ctx context.Context, definition Definition) error {
+	c.beforeNew(definition.ID)
+	if definition.Async {
+		c.newAsync(ctx, definition)
+		return nil
+	}
+	start := time.Now()
+	var instance any
+	var err error
+	c.traceRegion(ctx, "simpledi.New", definition.ID, func() {
+		instance, err = c.construct(ctx, definition, c.decorators[definition.ID])
+	})
+	elapsed := time.Since(start)
+	c.stats.recordNew(definition.ID, elapsed)
+	if err == nil {
+		c.store(definition.ID, instance)
+	}
+	c.afterNew(definition.ID, elapsed, err)
+	c.logNew(definition, c.dependencies(definition), elapsed, err)
+
+	return err
+}
+
+// construct creates the instance of the definition and applies the given decorators to it.
+func (c *Container) construct(ctx context.Context, definition Definition, decorators []decorator) (any, error) {
+	var instance any
+	var err error
+	switch {
+	case definition.build != nil:
+		instance, err = definition.build()
+	case definition.NewErr != nil:
+		instance, err = definition.Retry.run(ctx, definition.NewErr)
+	default:
+		instance = definition.New()
+	}
+	if err == nil {
+		instance, err = decorate(instance, decorators)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, definition.label())
+	}
+
+	return instance, nil
+}
+
+// rollback closes the instances of the given definitions in reverse order and removes them.
+func (c *Container) rollback(ctx context.Context, definitions []Definition) error {
+	const op = "simpledi.rollback"
+
+	errs := make([]error, 0)
+	for i := len(definitions) - 1; i >= 0; i-- {
+		definition := definitions[i]
+		if !c.built(definition.ID) {
+			continue
+		}
+		if definition.Close != nil {
+			if err := c.closeInstance(ctx, definition); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w (%s)", op, err, definition.label()))
+			}
+		}
+		c.remove(definition.ID)
+	}
+
+	return errors.Join(errs...)
+}
+
+// lookup returns the instance with the given ID.
+// Instances being closed after a rebuild take precedence over instances being rebuilt,
+// which take precedence over current ones.
+func (c *Container) lookup(id string) (any, bool) {
+	instance, _, ok := c.lookupVersioned(id)
+
+	return instance, ok
+}
+
+// lookupVersioned returns the instance with the given ID, like lookup, along with its generation.
+// An instance being rebuilt has the generation it gets once swapped in.
+func (c *Container) lookupVersioned(id string) (any, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if closing, ok := c.closing[id]; ok {
+		return closing.instance, closing.generation, true
+	}
+	if instance, ok := c.staging[id]; ok {
+		return instance, c.generations[id] + 1, true
+	}
+	instance, ok := c.instances[id]
+
+	return instance, c.generations[id], ok
+}
+
+// built reports whether the instance with the given ID was created,
+// by the running rebuild if there is one.
+func (c *Container) built(id string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.staging != nil {
+		_, ok := c.staging[id]
+		return ok
+	}
+	_, ok := c.instances[id]
+
+	return ok
+}
+
+// store saves the instance, as a rebuilt one if a rebuild is running.
+func (c *Container) store(id string, instance any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.staging != nil {
+		c.staging[id] = instance
+		return
+	}
+	c.instances[id] = instance
+	c.serial++
+	c.builds[id] = c.serial
+	c.generations[id]++
+}
+
+// remove deletes the instance, as a rebuilt one if a rebuild is running.
+func (c *Container) remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.staging != nil {
+		delete(c.staging, id)
+		return
+	}
+	delete(c.instances, id)
+	delete(c.builds, id)
+	delete(c.generations, id)
+}
+
+func (c *Container) closeInstance(ctx context.Context, definition Definition) error {
+	if definition.Async && c.failed(definition.ID) {
+		return nil
+	}
+	c.beforeClose(definition.ID)
+	start := time.Now()
+	var err error
+	c.traceRegion(ctx, "simpledi.Close", definition.ID, func() {
+		err = definition.Close()
+	})
+	elapsed := time.Since(start)
+	c.stats.recordClose(definition.ID, elapsed)
+	c.afterClose(definition.ID, elapsed, err)
+	c.logClose(definition, elapsed, err)
+
+	return err
+}
+
+// dependencies returns Deps, defined optional dependencies and dependencies of decorators.
+func (c *Container) dependencies(definition Definition) []string {
+	decorators := c.decorators[definition.ID]
+	if len(decorators) == 0 && len(definition.optional) == 0 {
+		return definition.Deps
+	}
+
+	deps := make([]string, 0, len(definition.Deps)+len(definition.optional))
+	deps = append(deps, definition.Deps...)
+	deps = append(deps, definition.optional...)
+	for _, d := range decorators {
+		deps = append(deps, d.deps...)
+	}
+
+	return deps
+}
+
+// prepare returns the definitions whose conditions hold, with groups and maps added,
+// and the ignored definitions.
+func (c *Container) prepare(definitions []Definition) ([]Definition, []Definition, error) {
+	definitions, ignored := c.filter(definitions)
+	definitions = append(definitions, c.groups(definitions, ignored)...)
+	maps, err := c.maps(definitions, ignored)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(definitions, maps...), ignored, nil
+}
+
+// requirements returns the given IDs and the IDs of all their dependencies, directly or transitively.
+// It expects definitions in topological order.
+func (c *Container) requirements(ids []string) (map[string]bool, error) {
+	const op = "simpledi.requirements"
+
+	result := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !c.defined(id) {
+			return nil, fmt.Errorf("%s: %w (ID: %s)", op, ErrIDNotFound, id)
+		}
+		result[id] = true
+	}
+	for i := len(c.definitions) - 1; i >= 0; i-- {
+		definition := c.definitions[i]
+		if !result[definition.ID] {
+			continue
+		}
+		for _, dependency := range c.dependencies(definition) {
+			result[dependency] = true
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Container) defined(id string) bool {
+	for _, definition := range c.definitions {
+		if definition.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependents returns the given ID and the IDs of all definitions that depend on it, directly or transitively.
+// It expects definitions in topological order.
+func (c *Container) dependents(id string) map[string]bool {
+	result := map[string]bool{id: true}
+	for _, definition := range c.definitions {
+		for _, dependency := range c.dependencies(definition) {
+			if result[dependency] {
+				result[definition.ID] = true
+				break
+			}
+		}
+	}
+
+	return result
+}
+
 func (c *Container) sort() error {
 	const op = "simpledi.sort"
 
 	definitionsCount := len(c.definitions)
-	if definitionsCount == 0 {
+	if definitionsCount == 0 && len(c.decorators) == 0 {
 		return nil
 	}
+	c.present()
 
 	inDegree := make(map[string]int, definitionsCount)
+	private := make(map[string]string)
+	async := make(map[string]bool)
 	for _, definition := range c.definitions {
 		if _, ok := inDegree[definition.ID]; ok {
-			return fmt.Errorf("%s: %w (ID: %s)", op, ErrIDDuplicate, definition.ID)
+			return fmt.Errorf("%s: %w (%s)", op, ErrIDDuplicate, definition.label())
+		}
+		inDegree[definition.ID] = len(c.dependencies(definition))
+		if definition.Private {
+			private[definition.ID] = definition.module
+		}
+		if definition.Async {
+			async[definition.ID] = true
+		}
+	}
+	for id := range c.decorators {
+		if _, ok := inDegree[id]; !ok {
+			return fmt.Errorf("%s: %w (Decorator: %s)", op, ErrIDNotFound, id)
 		}
-		inDegree[definition.ID] = len(definition.Deps)
 	}
 
 	queue := make([]Definition, 0, definitionsCount)
@@ -171,9 +648,15 @@ func (c *Container) sort() error {
 			queue = append(queue, definition)
 			continue
 		}
-		for _, dependency := range definition.Deps {
+		for _, dependency := range c.dependencies(definition) {
 			if _, ok := inDegree[dependency]; !ok {
-				return fmt.Errorf("%s: %w (ID: %s, Dependency: %s)", op, ErrDependencyNotFound, definition.ID, dependency)
+				return fmt.Errorf("%s: %w (%s, Dependency: %s)", op, ErrDependencyNotFound, definition.label(), dependency)
+			}
+			if module, ok := private[dependency]; ok && module != definition.module {
+				return fmt.Errorf("%s: %w (%s, Dependency: %s)", op, ErrDependencyPrivate, definition.label(), dependency)
+			}
+			if async[dependency] && (definition.synthetic || definition.build != nil) {
+				return fmt.Errorf("%s: %w (%s, Dependency: %s)", op, ErrDependencyAsync, definition.label(), dependency)
 			}
 			graph[dependency] = append(graph[dependency], definition)
 		}
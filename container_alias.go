@@ -0,0 +1,58 @@
+package simpledi
+
+import "fmt"
+
+// Alias makes the instance of target also available under the alias ID.
+// The alias depends on target and shares its instance, so the instance is closed once.
+func (c *Container) Alias(alias, target string) error {
+	const op = "simpledi.Alias"
+
+	if err := c.alias(alias, target, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// BindTo makes the instance of target available under the alias ID in the given container
+// and checks at Resolve that the instance implements T.
+func BindTo[T any](c *Container, alias, target string) error {
+	const op = "simpledi.Bind"
+
+	check := func(instance any) error {
+		if _, ok := instance.(T); !ok {
+			want := fmt.Sprintf("%T", (*T)(nil))[1:]
+			return fmt.Errorf("%w (Target: %s, Want: %s, Got: %T)", ErrTypeMismatch, target, want, instance)
+		}
+		return nil
+	}
+	if err := c.alias(alias, target, check); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c *Container) alias(alias, target string, check func(instance any) error) error {
+	if c.resolved {
+		return ErrContainerResolved
+	}
+	if alias == "" || target == "" {
+		return ErrIDRequired
+	}
+	c.register(Definition{
+		ID:   alias,
+		Deps: []string{target},
+		build: func() (any, error) {
+			instance, _ := c.lookup(target)
+			if check != nil {
+				if err := check(instance); err != nil {
+					return nil, err
+				}
+			}
+			return instance, nil
+		},
+	})
+
+	return nil
+}
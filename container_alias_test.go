@@ -0,0 +1,98 @@
+package simpledi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Alias_Same_Instance_Closed_Once(t *testing.T) {
+	c := simpledi.New()
+	closeCount := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "postgres_user_repo",
+			New: func() any {
+				return &ServiceImplA{}
+			},
+			Close: func() error {
+				closeCount++
+				return nil
+			},
+		})
+	})
+	assertNoError(t, func() error { return c.Alias("user_repo", "postgres_user_repo") })
+	assertNoError(t, c.Resolve)
+
+	repo, _ := c.Get("postgres_user_repo")
+	alias, _ := c.Get("user_repo")
+	assertSameValue(t, alias, repo)
+	assertOrder(t, c.Graph()["user_repo"], []string{"postgres_user_repo"})
+
+	assertNoError(t, c.Close)
+	assertSameValue(t, closeCount, 1)
+}
+
+func Test_Alias_Err(t *testing.T) {
+	c := simpledi.New()
+
+	assertError(t, func() error { return c.Alias("", "database") }, simpledi.ErrIDRequired)
+	assertError(t, func() error { return c.Alias("db", "") }, simpledi.ErrIDRequired)
+
+	assertNoError(t, func() error { return c.Alias("db", "database") })
+	assertError(t, c.Resolve, simpledi.ErrDependencyNotFound)
+	assertNoError(t, c.Close)
+
+	assertNoError(t, c.Resolve)
+	assertError(t, func() error { return c.Alias("db", "database") }, simpledi.ErrContainerResolved)
+	assertNoError(t, c.Close)
+}
+
+func Test_Bind(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "service_impl_a",
+			New: func() any {
+				return &ServiceImplA{}
+			},
+		})
+		simpledi.Bind[ServiceA]("service_a", "service_impl_a")
+		simpledi.Resolve()
+	})
+
+	assertSameValue(t, simpledi.Get[ServiceA]("service_a"), ServiceA(simpledi.Get[*ServiceImplA]("service_impl_a")))
+}
+
+func Test_Bind_Err_Type_Mismatch_Rolls_Back(t *testing.T) {
+	c := simpledi.New()
+	closed := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "service_impl_b",
+			New: func() any {
+				return &ServiceImplB{}
+			},
+			Close: func() error {
+				closed = append(closed, "service_impl_b")
+				return nil
+			},
+		})
+	})
+	assertNoError(t, func() error { return simpledi.BindTo[ServiceA](c, "service_a", "service_impl_b") })
+
+	err := c.Resolve()
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+	if !strings.Contains(err.Error(), "Want: simpledi_test.ServiceA, Got: *simpledi_test.ServiceImplB") {
+		t.Errorf("got: %v, want: types in error", err)
+	}
+	assertOrder(t, closed, []string{"service_impl_b"})
+	assertSameValue(t, c.Has("service_impl_b"), false)
+
+	assertNoError(t, c.Close)
+	assertOrder(t, closed, []string{"service_impl_b"})
+}
@@ -0,0 +1,195 @@
+package simpledi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Future is the instance of an Async definition until its New returns.
+type Future struct {
+	done     chan struct{}
+	cancel   context.CancelFunc
+	instance any
+	err      error
+}
+
+// Done returns a channel that is closed when New returns.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until New returns or ctx is done, and returns the created instance.
+func (f *Future) Wait(ctx context.Context) (any, error) {
+	select {
+	case <-f.done:
+		return f.instance, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// AwaitFrom returns an instance of type T by ID from the given container,
+// waiting for New to return if the definition is Async.
+// The error wraps ErrIDNotFound, ErrTypeMismatch, the error of New or the error of ctx.
+func AwaitFrom[T any](ctx context.Context, c *Container, id string) (T, error) {
+	const op = "simpledi.Await"
+
+	var zero T
+	instance, err := c.Get(id)
+	if err != nil {
+		return zero, err
+	}
+	if future, ok := instance.(*Future); ok {
+		if instance, err = future.Wait(ctx); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return zero, fmt.Errorf("%s: %w (ID: %s)", op, ctxErr, id)
+			}
+			return zero, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if instance == nil {
+		return zero, nil
+	}
+	typedInstance, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s: %w (ID: %s, Want: %T, Got: %T)", op, ErrTypeMismatch, id, zero, instance)
+	}
+
+	return typedInstance, nil
+}
+
+// Wait blocks until New of every Async definition returns or ctx is done.
+// Returns a combined error of the failed ones.
+func (c *Container) Wait(ctx context.Context) error {
+	const op = "simpledi.Wait"
+
+	c.rebuildMu.Lock()
+	futures := c.futures()
+	c.rebuildMu.Unlock()
+
+	errs := make([]error, 0)
+	for _, a := range futures {
+		if a.future == nil {
+			continue
+		}
+		if _, err := a.future.Wait(ctx); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fmt.Errorf("%s: %w (ID: %s)", op, ctxErr, a.id)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", op, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Health returns the state of every Async definition, keyed by ID:
+// nil once New succeeded, ErrAsyncPending while it is running, or the error of New.
+func (c *Container) Health() map[string]error {
+	c.rebuildMu.Lock()
+	futures := c.futures()
+	c.rebuildMu.Unlock()
+
+	health := make(map[string]error, len(futures))
+	for _, a := range futures {
+		if a.future == nil {
+			health[a.id] = nil
+			continue
+		}
+		select {
+		case <-a.future.done:
+			health[a.id] = a.future.err
+		default:
+			health[a.id] = ErrAsyncPending
+		}
+	}
+
+	return health
+}
+
+// newAsync stores a Future for the definition and runs New in a goroutine.
+// Once New succeeds, the created instance replaces the Future.
+// The goroutine only uses the decorators and dependencies the definition has when it starts,
+// so that Restore may replace them.
+func (c *Container) newAsync(ctx context.Context, definition Definition) {
+	ctx, cancel := context.WithCancel(ctx)
+	future := &Future{done: make(chan struct{}), cancel: cancel}
+	c.store(definition.ID, future)
+	c.mu.RLock()
+	staging := c.staging
+	lifetime := c.lifetime
+	c.mu.RUnlock()
+	decorators := c.decorators[definition.ID]
+	deps := c.dependencies(definition)
+
+	stop := context.AfterFunc(lifetime, cancel)
+	go func() {
+		defer cancel()
+		defer stop()
+		start := time.Now()
+		c.traceRegion(ctx, "simpledi.New", definition.ID, func() {
+			future.instance, future.err = c.construct(ctx, definition, decorators)
+		})
+		elapsed := time.Since(start)
+		c.stats.recordNew(definition.ID, elapsed)
+		c.afterNew(definition.ID, elapsed, future.err)
+		c.logNew(definition, deps, elapsed, future.err)
+		if future.err == nil {
+			c.settle(definition.ID, future, staging)
+		}
+		close(future.done)
+	}()
+}
+
+// settle replaces the Future with its instance, unless another instance was stored since.
+func (c *Container) settle(id string, future *Future, staging map[string]any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, instances := range []map[string]any{c.instances, staging} {
+		if f, ok := instances[id].(*Future); ok && f == future {
+			instances[id] = future.instance
+		}
+	}
+}
+
+type asyncInstance struct {
+	id     string
+	future *Future
+}
+
+// futures returns the current instances of Async definitions in definition order,
+// with a nil Future for those already replaced by their instance.
+func (c *Container) futures() []asyncInstance {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	futures := make([]asyncInstance, 0)
+	for _, definition := range c.definitions {
+		if !definition.Async {
+			continue
+		}
+		instance, ok := c.instances[definition.ID]
+		if !ok {
+			continue
+		}
+		future, _ := instance.(*Future)
+		futures = append(futures, asyncInstance{id: definition.ID, future: future})
+	}
+
+	return futures
+}
+
+// failed waits for New of the Async definition with the given ID and reports whether it failed.
+func (c *Container) failed(id string) bool {
+	instance, _ := c.lookup(id)
+	future, ok := instance.(*Future)
+	if !ok {
+		return false
+	}
+	<-future.done
+
+	return future.err != nil
+}
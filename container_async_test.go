@@ -0,0 +1,278 @@
+package simpledi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Async_Dependents_Get_Future(t *testing.T) {
+	c := simpledi.New()
+	release := make(chan struct{})
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "model",
+			Async: true,
+			New: func() any {
+				<-release
+				return "model"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "handler",
+			Deps: []string{"model"},
+			New: func() any {
+				future, _ := simpledi.GetFrom[*simpledi.Future](c, "model")
+				return future
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error { return c.Health()["model"] }, simpledi.ErrAsyncPending)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assertError(t, func() error { return c.Wait(ctx) }, context.DeadlineExceeded)
+
+	close(release)
+	assertNoError(t, func() error { return c.Wait(context.Background()) })
+	assertNoError(t, func() error { return c.Health()["model"] })
+
+	handler, _ := simpledi.GetFrom[*simpledi.Future](c, "handler")
+	model, err := handler.Wait(context.Background())
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, model, any("model"))
+}
+
+func Test_Async_Failure(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("some error")
+	closed := false
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "cache",
+			Async: true,
+			NewErr: func() (any, error) {
+				return nil, someError
+			},
+			Close: func() error {
+				closed = true
+				return nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+
+	assertError(t, func() error { return c.Wait(context.Background()) }, someError)
+	assertError(t, func() error { return c.Health()["cache"] }, someError)
+	_, err := simpledi.AwaitFrom[string](context.Background(), c, "cache")
+	assertError(t, func() error { return err }, someError)
+
+	assertNoError(t, c.Close)
+	assertSameValue(t, closed, false)
+}
+
+func Test_AwaitFrom(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "model",
+			Async: true,
+			New: func() any {
+				return &ServiceImplB{data: "weights"}
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	model, err := simpledi.AwaitFrom[*ServiceImplB](context.Background(), c, "model")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, model.data, "weights")
+
+	_, err = simpledi.AwaitFrom[*ServiceImplA](context.Background(), c, "model")
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+	_, err = simpledi.AwaitFrom[*ServiceImplA](context.Background(), c, "cache")
+	assertError(t, func() error { return err }, simpledi.ErrIDNotFound)
+}
+
+func Test_NewErr(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("some error")
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			NewErr: func() (any, error) {
+				return nil, someError
+			},
+		})
+	})
+	assertError(t, c.Resolve, someError)
+	assertNoError(t, c.Close)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			NewErr: func() (any, error) {
+				return "postgres", nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	database, _ := c.Get("database")
+	assertSameValue(t, database, any("postgres"))
+}
+
+func Test_Async_Retry_Stops(t *testing.T) {
+	someError := errors.New("connection refused")
+	definition := simpledi.Definition{
+		ID:    "model",
+		Async: true,
+		NewErr: func() (any, error) {
+			return nil, someError
+		},
+		Retry: simpledi.RetryPolicy{
+			MaxAttempts: 1000,
+			Backoff:     time.Hour,
+		},
+	}
+
+	t.Run("context", func(t *testing.T) {
+		c := simpledi.New()
+		ctx, cancel := context.WithCancel(context.Background())
+		assertNoError(t, func() error { return c.Set(definition) })
+		assertNoError(t, func() error { return c.ResolveContext(ctx) })
+
+		cancel()
+		err := c.Wait(context.Background())
+		assertError(t, func() error { return err }, context.Canceled)
+		assertError(t, func() error { return err }, someError)
+		assertNoError(t, c.Close)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		c := simpledi.New()
+		assertNoError(t, func() error { return c.Set(definition) })
+		assertNoError(t, c.Resolve)
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			_ = c.Close()
+		}()
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got: Close blocked, want: Close returned")
+		}
+	})
+}
+
+func Test_Async_Instance_Replaces_Future(t *testing.T) {
+	defer simpledi.Close()
+	var closed *ServiceImplB
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID:    "model",
+			Async: true,
+			New: func() any {
+				return &ServiceImplB{data: "weights"}
+			},
+			Close: func() error {
+				closed = simpledi.Get[*ServiceImplB]("model")
+				return nil
+			},
+		})
+		simpledi.Resolve()
+		simpledi.Wait(context.Background())
+	})
+
+	model := simpledi.Get[*ServiceImplB]("model")
+	assertSameValue(t, model.data, "weights")
+	assertSamePointer(t, simpledi.Await[*ServiceImplB](context.Background(), "model"), model)
+	assertSameValue(t, len(simpledi.Health()), 1)
+	assertNoError(t, func() error { return simpledi.Health()["model"] })
+
+	simpledi.Close()
+	assertSamePointer(t, closed, model)
+}
+
+func Test_Async_Err_Dependency_Async(t *testing.T) {
+	model := simpledi.Definition{
+		ID:    "model",
+		Async: true,
+		Group: "models",
+		New: func() any {
+			return &ServiceImplA{}
+		},
+	}
+
+	c := simpledi.New()
+	assertNoError(t, func() error { return c.Set(model) })
+	assertError(t, c.Resolve, simpledi.ErrDependencyAsync)
+
+	c = simpledi.New()
+	model.Group = ""
+	assertNoError(t, func() error { return c.Set(model) })
+	assertNoError(t, func() error { return simpledi.BindTo[ServiceA](c, "service", "model") })
+	assertError(t, c.Resolve, simpledi.ErrDependencyAsync)
+}
+
+func Test_Async_Restore_Stops(t *testing.T) {
+	c := simpledi.New()
+	snapshot := c.Snapshot()
+	someError := errors.New("connection refused")
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "model",
+			Async: true,
+			NewErr: func() (any, error) {
+				return nil, someError
+			},
+			Retry: simpledi.RetryPolicy{
+				MaxAttempts: 1000,
+				Backoff:     time.Hour,
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return simpledi.DecorateFrom(c, "model", func(model string) string {
+			return model + " (cached)"
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	instance, _ := c.Get("model")
+	future := instance.(*simpledi.Future)
+
+	restored := make(chan error)
+	go func() {
+		restored <- c.Restore(snapshot)
+	}()
+	select {
+	case err := <-restored:
+		assertNoError(t, func() error { return err })
+	case <-time.After(5 * time.Second):
+		t.Fatalf("got: Restore blocked, want: Restore returned")
+	}
+	select {
+	case <-future.Done():
+	default:
+		t.Fatalf("got: New running, want: New returned")
+	}
+	assertSameValue(t, c.Has("model"), false)
+}
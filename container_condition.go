@@ -0,0 +1,88 @@
+package simpledi
+
+import (
+	"os"
+	"slices"
+)
+
+// Condition reports whether a definition is registered at Resolve.
+type Condition func(c *Container) bool
+
+// WithProfiles sets the active profiles of the container.
+func WithProfiles(profiles ...string) Option {
+	return func(c *Container) {
+		c.profiles = append(c.profiles, profiles...)
+	}
+}
+
+// Profiles returns the active profiles of the container.
+func (c *Container) Profiles() []string {
+	return slices.Clone(c.profiles)
+}
+
+// Ignored returns the IDs of definitions excluded by their conditions at the last Resolve.
+// If Resolve fails, the conditions are evaluated again by the next one.
+func (c *Container) Ignored() []string {
+	ids := make([]string, len(c.ignored))
+	for i, definition := range c.ignored {
+		ids[i] = definition.ID
+	}
+
+	return ids
+}
+
+// IfProfile returns a condition that holds if any of the given profiles is active.
+func IfProfile(profiles ...string) Condition {
+	return func(c *Container) bool {
+		for _, profile := range profiles {
+			if slices.Contains(c.profiles, profile) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IfEnv returns a condition that holds if the environment variable is present.
+func IfEnv(key string) Condition {
+	return func(*Container) bool {
+		_, ok := os.LookupEnv(key)
+		return ok
+	}
+}
+
+// If returns a condition that holds if fn returns true.
+func If(fn func() bool) Condition {
+	return func(*Container) bool {
+		return fn()
+	}
+}
+
+// filter splits definitions into those whose conditions hold and those that are ignored,
+// dropping definitions added by a previous Resolve.
+func (c *Container) filter(definitions []Definition) ([]Definition, []Definition) {
+	matched := make([]Definition, 0, len(definitions))
+	ignored := make([]Definition, 0)
+	for _, definition := range definitions {
+		if definition.synthetic {
+			continue
+		}
+		if c.matches(definition) {
+			matched = append(matched, definition)
+		} else {
+			ignored = append(ignored, definition)
+		}
+	}
+
+	return matched, ignored
+}
+
+func (c *Container) matches(definition Definition) bool {
+	for _, condition := range definition.When {
+		if !condition(c) {
+			return false
+		}
+	}
+
+	return true
+}
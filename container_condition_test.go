@@ -0,0 +1,142 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_When_Profile(t *testing.T) {
+	newContainer := func(profiles ...string) *simpledi.Container {
+		c := simpledi.New(simpledi.WithProfiles(profiles...))
+		for _, d := range []simpledi.Definition{
+			{
+				ID:   "cache",
+				When: []simpledi.Condition{simpledi.IfProfile("local", "test")},
+				New: func() any {
+					return "memory"
+				},
+			},
+			{
+				ID:   "cache",
+				When: []simpledi.Condition{simpledi.IfProfile("prod")},
+				New: func() any {
+					return "redis"
+				},
+			},
+			{
+				ID:   "service",
+				Deps: []string{"cache"},
+				New: func() any {
+					return "service"
+				},
+			},
+		} {
+			assertNoError(t, func() error { return c.Set(d) })
+		}
+		return c
+	}
+
+	local := newContainer("local")
+	assertNoError(t, local.Resolve)
+	defer local.Close()
+	cache, _ := local.Get("cache")
+	assertSameValue(t, cache, any("memory"))
+	assertOrder(t, local.Ignored(), []string{"cache"})
+	assertOrder(t, local.Profiles(), []string{"local"})
+
+	prod := newContainer("prod")
+	assertNoError(t, prod.Resolve)
+	defer prod.Close()
+	cache, _ = prod.Get("cache")
+	assertSameValue(t, cache, any("redis"))
+
+	none := newContainer()
+	assertError(t, none.Resolve, simpledi.ErrDependencyNotFound)
+	assertOrder(t, none.Ignored(), []string{"cache", "cache"})
+	assertNoError(t, none.Close)
+	assertSameValue(t, len(none.Ignored()), 0)
+}
+
+func Test_When_Env_And_Predicate(t *testing.T) {
+	t.Setenv("SIMPLEDI_TEST_TRACING", "1")
+	c := simpledi.New()
+
+	for _, d := range []simpledi.Definition{
+		{
+			ID:   "tracer",
+			When: []simpledi.Condition{simpledi.IfEnv("SIMPLEDI_TEST_TRACING")},
+			New: func() any {
+				return "tracer"
+			},
+		},
+		{
+			ID:   "metrics",
+			When: []simpledi.Condition{simpledi.IfEnv("SIMPLEDI_TEST_METRICS")},
+			New: func() any {
+				return "metrics"
+			},
+		},
+		{
+			ID: "profiler",
+			When: []simpledi.Condition{
+				simpledi.IfEnv("SIMPLEDI_TEST_TRACING"),
+				simpledi.If(func() bool { return false }),
+			},
+			New: func() any {
+				return "profiler"
+			},
+		},
+	} {
+		assertNoError(t, func() error { return c.Set(d) })
+	}
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	_, ok := simpledi.LookupFrom[string](c, "tracer")
+	assertSameValue(t, ok, true)
+	assertOrder(t, c.Ignored(), []string{"metrics", "profiler"})
+}
+
+func Test_When_Reevaluated_After_Failed_Resolve(t *testing.T) {
+	c := simpledi.New()
+	enabled := false
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "metrics",
+			When: []simpledi.Condition{simpledi.If(func() bool {
+				return enabled
+			})},
+			New: func() any {
+				return "metrics"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service",
+			Deps: []string{"database"},
+			New: func() any {
+				return "service"
+			},
+		})
+	})
+	assertError(t, c.Resolve, simpledi.ErrDependencyNotFound)
+	assertOrder(t, c.Ignored(), []string{"metrics"})
+
+	enabled = true
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertSameValue(t, c.Has("metrics"), true)
+	assertSameValue(t, len(c.Ignored()), 0)
+}
@@ -0,0 +1,82 @@
+package simpledi
+
+import "fmt"
+
+type decorator struct {
+	deps []string
+	fn   func(any) (any, error)
+}
+
+// Decorate wraps the instance of the definition with the given ID.
+// Decorators are applied in registration order right after New,
+// before any dependent definition is created.
+// deps are resolved before the decorated definition.
+func (c *Container) Decorate(id string, fn func(any) any, deps ...string) error {
+	const op = "simpledi.Decorate"
+
+	var decorator func(any) (any, error)
+	if fn != nil {
+		decorator = func(instance any) (any, error) {
+			return fn(instance), nil
+		}
+	}
+	if err := c.addDecorator(id, decorator, deps); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DecorateFrom wraps the instance of type T of the definition with the given ID in the given container,
+// in the same way as Decorate.
+// If the instance has another type, Resolve returns an error wrapping ErrTypeMismatch.
+func DecorateFrom[T any](c *Container, id string, fn func(T) T, deps ...string) error {
+	const op = "simpledi.Decorate"
+
+	var decorator func(any) (any, error)
+	if fn != nil {
+		decorator = func(instance any) (any, error) {
+			if instance == nil {
+				var zero T
+				return fn(zero), nil
+			}
+			typedInstance, ok := instance.(T)
+			if !ok {
+				want := fmt.Sprintf("%T", (*T)(nil))[1:]
+				return nil, fmt.Errorf("%w (Want: %s, Got: %T)", ErrTypeMismatch, want, instance)
+			}
+			return fn(typedInstance), nil
+		}
+	}
+	if err := c.addDecorator(id, decorator, deps); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c *Container) addDecorator(id string, fn func(any) (any, error), deps []string) error {
+	if c.resolved {
+		return ErrContainerResolved
+	}
+	if id == "" {
+		return ErrIDRequired
+	}
+	if fn == nil {
+		return fmt.Errorf("%w (ID: %s)", ErrDecoratorRequired, id)
+	}
+	c.decorators[id] = append(c.decorators[id], decorator{deps: deps, fn: fn})
+
+	return nil
+}
+
+func decorate(instance any, decorators []decorator) (any, error) {
+	for _, d := range decorators {
+		var err error
+		if instance, err = d.fn(instance); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
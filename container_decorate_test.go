@@ -0,0 +1,224 @@
+package simpledi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Decorate_Registration_Order(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "bread",
+			New: func() any {
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Decorate("bread", func(instance any) any {
+			return instance.(string) + "+butter"
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Decorate("bread", func(instance any) any {
+			return instance.(string) + "+jam"
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	bread, err := c.Get("bread")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, bread, any("bread+butter+jam"))
+}
+
+func Test_Decorate_Before_Dependents(t *testing.T) {
+	c := simpledi.New()
+	var got any
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "flour",
+			New: func() any {
+				return "flour"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "bread",
+			Deps: []string{"flour"},
+			New: func() any {
+				got, _ = c.Get("flour")
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Decorate("flour", func(instance any) any {
+			return "sifted " + instance.(string)
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertSameValue(t, got, any("sifted flour"))
+}
+
+func Test_Decorate_With_Deps(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "bread",
+			New: func() any {
+				order = append(order, "bread")
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "butter",
+			New: func() any {
+				order = append(order, "butter")
+				return "butter"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Decorate("bread", func(instance any) any {
+			butter, _ := c.Get("butter")
+			return instance.(string) + "+" + butter.(string)
+		}, "butter")
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertOrder(t, order, []string{"butter", "bread"})
+	bread, _ := c.Get("bread")
+	assertSameValue(t, bread, any("bread+butter"))
+}
+
+func Test_Decorate_Err(t *testing.T) {
+	c := simpledi.New()
+
+	assertError(t, func() error {
+		return c.Decorate("", func(instance any) any { return instance })
+	}, simpledi.ErrIDRequired)
+	assertError(t, func() error {
+		return c.Decorate("bread", nil)
+	}, simpledi.ErrDecoratorRequired)
+
+	assertNoError(t, func() error {
+		return c.Decorate("bread", func(instance any) any { return instance })
+	})
+	assertError(t, c.Resolve, simpledi.ErrIDNotFound)
+	assertNoError(t, c.Close)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "bread",
+			New: func() any {
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Decorate("bread", func(instance any) any { return instance }, "butter")
+	})
+	assertError(t, c.Resolve, simpledi.ErrDependencyNotFound)
+	assertNoError(t, c.Close)
+
+	assertNoError(t, c.Resolve)
+	assertError(t, func() error {
+		return c.Decorate("bread", func(instance any) any { return instance })
+	}, simpledi.ErrContainerResolved)
+	assertNoError(t, c.Close)
+}
+
+func Test_Decorate_Generic(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "service_2",
+			New: func() any {
+				return &ServiceImplB{data: "data"}
+			},
+		})
+		simpledi.Decorate("service_2", func(s *ServiceImplB) *ServiceImplB {
+			return &ServiceImplB{data: s.data + "+decorated"}
+		})
+		simpledi.Resolve()
+	})
+
+	assertSameValue(t, simpledi.Get[*ServiceImplB]("service_2").data, "data+decorated")
+}
+
+func Test_Decorate_Generic_Err_Type_Mismatch(t *testing.T) {
+	defer simpledi.Close()
+
+	assertPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "service_1",
+			New: func() any {
+				return &ServiceImplA{}
+			},
+		})
+		simpledi.Decorate("service_1", func(s *ServiceImplB) *ServiceImplB {
+			return s
+		})
+		simpledi.Resolve()
+	}, simpledi.ErrTypeMismatch)
+}
+
+func Test_DecorateFrom_Err_Type_Mismatch_Rolls_Back(t *testing.T) {
+	c := simpledi.New()
+	closed := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "service_2",
+			New: func() any {
+				return &ServiceImplB{data: "data"}
+			},
+			Close: func() error {
+				closed = append(closed, "service_2")
+				return nil
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service_1",
+			Deps: []string{"service_2"},
+			New: func() any {
+				return &ServiceImplA{}
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return simpledi.DecorateFrom(c, "service_2", func(s *ServiceImplB) *ServiceImplB {
+			return &ServiceImplB{data: s.data + "+decorated"}
+		})
+	})
+	assertNoError(t, func() error {
+		return simpledi.DecorateFrom(c, "service_1", func(s *ServiceImplB) *ServiceImplB {
+			return s
+		})
+	})
+
+	err := c.Resolve()
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+	assertOrder(t, closed, []string{"service_2"})
+	if !strings.Contains(err.Error(), "Want: *simpledi_test.ServiceImplB, Got: *simpledi_test.ServiceImplA") {
+		t.Errorf("got: %q, want: type names", err)
+	}
+	assertNoError(t, c.Close)
+}
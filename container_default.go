@@ -1,13 +1,44 @@
 package simpledi
 
 import (
-	"fmt"
-	"sync"
+	"context"
+	"sync/atomic"
 )
 
-var container = sync.OnceValue(func() *Container {
-	return New()
-})
+var defaultContainer atomic.Pointer[Container]
+
+func container() *Container {
+	if c := defaultContainer.Load(); c != nil {
+		return c
+	}
+	defaultContainer.CompareAndSwap(nil, New())
+
+	return defaultContainer.Load()
+}
+
+// Default returns the container used by the package-level functions.
+func Default() *Container {
+	return container()
+}
+
+// SetDefault replaces the container used by the package-level functions
+// and returns the previous one. A nil c installs a new empty container.
+func SetDefault(c *Container) *Container {
+	if c == nil {
+		c = New()
+	}
+
+	return defaultContainer.Swap(c)
+}
+
+// Reset closes the container used by the package-level functions
+// and replaces it with a new empty one.
+func Reset() error {
+	err := container().Close()
+	SetDefault(nil)
+
+	return err
+}
 
 // Set adds a definition to the container.
 func Set(d Definition) {
@@ -16,25 +47,97 @@ func Set(d Definition) {
 	}
 }
 
+// Install adds all definitions of the module and its nested modules to the container.
+func Install(m Module) {
+	if err := container().Install(m); err != nil {
+		panic(err)
+	}
+}
+
+// Alias makes the instance of target also available under the alias ID.
+func Alias(alias, target string) {
+	if err := container().Alias(alias, target); err != nil {
+		panic(err)
+	}
+}
+
+// Bind makes the instance of target available under the alias ID
+// and checks at Resolve that the instance implements T.
+func Bind[T any](alias, target string) {
+	if err := BindTo[T](container(), alias, target); err != nil {
+		panic(err)
+	}
+}
+
+// Override replaces an existing definition with the same ID.
+func Override(d Definition) {
+	if err := container().Override(d); err != nil {
+		panic(err)
+	}
+}
+
+// Replace swaps the definition with the given ID in the default container.
+func Replace(id string, d Definition) {
+	if err := container().Replace(id, d); err != nil {
+		panic(err)
+	}
+}
+
 // Get returns an instance by ID.
 func Get[T any](id string) T {
-	const op = "simpledi.Get"
-
-	var zero T
-	instance, err := container().Get(id)
+	instance, err := GetFrom[T](container(), id)
 	if err != nil {
 		panic(err)
 	}
-	if instance == nil {
-		return zero
+
+	return instance
+}
+
+// TryGet returns an instance by ID.
+// The error wraps ErrIDNotFound or ErrTypeMismatch.
+func TryGet[T any](id string) (T, error) {
+	return GetFrom[T](container(), id)
+}
+
+// Lookup returns an instance by ID.
+// The boolean is false if the ID is not found or the instance has another type.
+func Lookup[T any](id string) (T, bool) {
+	return LookupFrom[T](container(), id)
+}
+
+// Has reports whether an instance with the given ID exists.
+func Has(id string) bool {
+	return container().Has(id)
+}
+
+// GetGroup returns the instances of a group by its name.
+func GetGroup[T any](id string) []T {
+	instances, err := GroupFrom[T](container(), id)
+	if err != nil {
+		panic(err)
 	}
-	typedInstance, ok := instance.(T)
-	if !ok {
-		err := fmt.Errorf("%s: %w (ID: %s, Want: %T, Got: %T)", op, ErrTypeMismatch, id, zero, instance)
+
+	return instances
+}
+
+// GetMap returns the instances of a map by its name.
+func GetMap[T any](id string) map[string]T {
+	instances, err := MapFrom[T](container(), id)
+	if err != nil {
 		panic(err)
 	}
 
-	return typedInstance
+	return instances
+}
+
+// Decorate wraps the instance of the definition with the given ID.
+// Decorators are applied in registration order right after New,
+// before any dependent definition is created.
+// deps are resolved before the decorated definition.
+func Decorate[T any](id string, fn func(T) T, deps ...string) {
+	if err := DecorateFrom(container(), id, fn, deps...); err != nil {
+		panic(err)
+	}
 }
 
 // Resolve creates instances for all registered definitions.
@@ -45,6 +148,67 @@ func Resolve() {
 	}
 }
 
+// ResolveContext is like Resolve, but stops retrying constructors once ctx is done.
+func ResolveContext(ctx context.Context) {
+	if err := container().ResolveContext(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// ResolveOnly creates instances for the given IDs and their dependencies, directly or transitively.
+// All definitions are still validated, the rest are left unbuilt.
+func ResolveOnly(ids ...string) {
+	if err := container().ResolveOnly(ids...); err != nil {
+		panic(err)
+	}
+}
+
+// Extend adds definitions to a resolved container and creates their instances.
+func Extend(ds ...Definition) {
+	if err := container().Extend(ds...); err != nil {
+		panic(err)
+	}
+}
+
+// Reload creates Reloadable definitions of the default container and their dependents again.
+func Reload() {
+	if err := container().Reload(); err != nil {
+		panic(err)
+	}
+}
+
+// Watch calls fn on every change of the instance with the given ID in the default container.
+func Watch(id string, fn func(Event)) (cancel func()) {
+	return container().Watch(id, fn)
+}
+
+// Generation returns the generation of the instance with the given ID in the default container.
+func Generation(id string) uint64 {
+	return container().Generation(id)
+}
+
+// Await returns an instance by ID, waiting for New to return if the definition is Async.
+func Await[T any](ctx context.Context, id string) T {
+	instance, err := AwaitFrom[T](ctx, container(), id)
+	if err != nil {
+		panic(err)
+	}
+
+	return instance
+}
+
+// Wait blocks until New of every Async definition in the default container returns or ctx is done.
+func Wait(ctx context.Context) {
+	if err := container().Wait(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Health returns the state of every Async definition in the default container, keyed by ID.
+func Health() map[string]error {
+	return container().Health()
+}
+
 // Close calls Close for all definitions that provide it, in reverse order.
 // Returns a combined error if any Close calls fail.
 // The container is then cleared and can be reused.
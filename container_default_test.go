@@ -0,0 +1,74 @@
+package simpledi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_SetDefault(t *testing.T) {
+	c := simpledi.New()
+	previous := simpledi.SetDefault(c)
+	defer simpledi.SetDefault(previous)
+
+	if simpledi.Default() != c {
+		t.Errorf("got: %p, want: %p", simpledi.Default(), c)
+	}
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	yeast, err := c.Get("yeast")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, yeast, any("yeast"))
+	assertError(t, func() error {
+		_, err := previous.Get("yeast")
+		return err
+	}, simpledi.ErrIDNotFound)
+}
+
+func Test_SetDefault_Nil(t *testing.T) {
+	previous := simpledi.SetDefault(nil)
+	defer simpledi.SetDefault(previous)
+
+	if simpledi.Default() == nil || simpledi.Default() == previous {
+		t.Errorf("got: %p, want: new container", simpledi.Default())
+	}
+}
+
+func Test_Reset(t *testing.T) {
+	someError := errors.New("some error")
+	previous := simpledi.SetDefault(simpledi.New())
+	defer simpledi.SetDefault(previous)
+	c := simpledi.Default()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+			Close: func() error {
+				_ = simpledi.Get[string]("yeast")
+				return someError
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	assertError(t, simpledi.Reset, someError)
+	if simpledi.Default() == c {
+		t.Errorf("got: %p, want: new container", c)
+	}
+	assertPanic(t, func() {
+		_ = simpledi.Get[string]("yeast")
+	}, simpledi.ErrIDNotFound)
+}
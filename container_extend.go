@@ -0,0 +1,116 @@
+package simpledi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Extend adds definitions to a resolved container and creates their instances
+// in topological order. New definitions may depend on existing ones,
+// existing instances are neither created again nor closed,
+// except groups and maps that get new members: they are swapped along with their dependents as by Replace.
+// Nothing is added if any definition is invalid or any New fails.
+func (c *Container) Extend(ds ...Definition) error {
+	const op = "simpledi.Extend"
+
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
+	if !c.resolved {
+		return fmt.Errorf("%s: %w", op, ErrContainerNotResolved)
+	}
+	for _, d := range ds {
+		if d.ID == "" {
+			return fmt.Errorf("%s: %w", op, ErrIDRequired)
+		}
+		if d.New == nil && d.NewErr == nil {
+			return fmt.Errorf("%s: %w (ID: %s)", op, ErrNewRequired, d.ID)
+		}
+	}
+
+	previous := c.definitions
+	matched, ignored := c.filter(ds)
+	matched = c.sequence(matched)
+	ignored = append(slices.Clip(c.ignored), ignored...)
+	definitions := make([]Definition, 0, len(previous)+len(matched))
+	existing := make(map[string]Definition)
+	for _, definition := range previous {
+		if definition.synthetic {
+			existing[definition.ID] = definition
+			continue
+		}
+		definitions = append(definitions, definition)
+	}
+	definitions = append(definitions, matched...)
+	slices.SortStableFunc(definitions, func(a, b Definition) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	synthetic := c.groups(definitions, ignored)
+	maps, err := c.maps(definitions, ignored)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	synthetic = append(synthetic, maps...)
+
+	added := slices.Clip(matched)
+	changed := make([]string, 0)
+	for _, definition := range synthetic {
+		old, ok := existing[definition.ID]
+		if !ok {
+			added = append(added, definition)
+			continue
+		}
+		if !slices.Equal(old.Deps, definition.Deps) {
+			changed = append(changed, definition.ID)
+		}
+	}
+	c.definitions = append(definitions, synthetic...)
+	if err := c.sort(); err != nil {
+		c.definitions = previous
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ids := make([]string, len(added))
+	for i, definition := range added {
+		ids[i] = definition.ID
+	}
+	required, err := c.requirements(ids)
+	if err != nil {
+		c.definitions = previous
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.stats.add(added, c.dependencies)
+	ctx, end := c.traceTask(context.Background(), op)
+	defer end()
+	built := make([]Definition, 0, len(required))
+	for _, definition := range c.definitions {
+		if !required[definition.ID] || c.Has(definition.ID) {
+			continue
+		}
+		if err := c.newInstance(ctx, definition); err != nil {
+			err = errors.Join(err, c.rollback(ctx, built))
+			c.stats.remove(added)
+			c.definitions = previous
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		built = append(built, definition)
+	}
+	if len(changed) == 0 {
+		c.ignored = ignored
+		return nil
+	}
+	swapped, err := c.rebuild(ctx, previous, changed...)
+	if swapped == nil {
+		c.stats.remove(added)
+		return fmt.Errorf("%s: %w", op, errors.Join(err, c.rollback(ctx, built)))
+	}
+	c.ignored = ignored
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
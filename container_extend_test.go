@@ -0,0 +1,277 @@
+package simpledi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Extend_Builds_New_Subgraph(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+	closed := make([]string, 0)
+	definition := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				order = append(order, id)
+				return id
+			},
+			Close: func() error {
+				closed = append(closed, id)
+				return nil
+			},
+		}
+	}
+
+	assertNoError(t, func() error { return c.Set(definition("config")) })
+	assertNoError(t, func() error { return c.Set(definition("database", "config")) })
+	assertNoError(t, func() error { return c.Set(definition("cache", "config")) })
+	assertNoError(t, func() error { return c.ResolveOnly("database") })
+
+	assertNoError(t, func() error {
+		return c.Extend(
+			definition("plugin_routes", "plugin"),
+			definition("plugin", "cache", "database"),
+		)
+	})
+	assertOrder(t, order, []string{"config", "database", "cache", "plugin", "plugin_routes"})
+	assertSameValue(t, len(closed), 0)
+
+	assertNoError(t, c.Close)
+	assertOrder(t, closed, []string{"plugin_routes", "plugin", "cache", "database", "config"})
+}
+
+func Test_Extend_Generic(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "config"
+			},
+		})
+		simpledi.Resolve()
+		simpledi.Extend(simpledi.Definition{
+			ID:   "plugin",
+			Deps: []string{"config"},
+			New: func() any {
+				return "plugin with " + simpledi.Get[string]("config")
+			},
+		})
+	})
+
+	assertSameValue(t, simpledi.Get[string]("plugin"), "plugin with config")
+}
+
+func Test_Extend_Err(t *testing.T) {
+	c := simpledi.New()
+	plugin := simpledi.Definition{
+		ID:   "plugin",
+		Deps: []string{"config"},
+		New: func() any {
+			return "plugin"
+		},
+	}
+
+	assertError(t, func() error { return c.Extend(plugin) }, simpledi.ErrContainerNotResolved)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "config"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error { return c.Extend(simpledi.Definition{ID: "plugin"}) }, simpledi.ErrNewRequired)
+	assertError(t, func() error {
+		return c.Extend(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "config"
+			},
+		})
+	}, simpledi.ErrIDDuplicate)
+	assertError(t, func() error {
+		return c.Extend(simpledi.Definition{
+			ID:   "plugin",
+			Deps: []string{"missing"},
+			New: func() any {
+				return "plugin"
+			},
+		})
+	}, simpledi.ErrDependencyNotFound)
+
+	assertNoError(t, func() error { return c.Extend(plugin) })
+	assertOrder(t, c.Graph()["plugin"], []string{"config"})
+}
+
+func Test_Extend_Group_And_Map_Members(t *testing.T) {
+	c := simpledi.New()
+	check := func(id string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:    id,
+			Group: "checks",
+			Map:   "checks_by_name",
+			Key:   id,
+			New: func() any {
+				return healthCheck(id)
+			},
+		}
+	}
+
+	assertNoError(t, func() error { return c.Set(check("database")) })
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "server",
+			Deps: []string{"checks", "checks_by_name"},
+			New: func() any {
+				checks, _ := simpledi.GroupFrom[HealthCheck](c, "checks")
+				byName, _ := simpledi.MapFrom[HealthCheck](c, "checks_by_name")
+				return len(checks) + len(byName)
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertNoError(t, func() error { return c.Extend(check("plugin")) })
+
+	checks, _ := simpledi.GroupFrom[HealthCheck](c, "checks")
+	assertSameValue(t, len(checks), 2)
+	assertSameValue(t, checks[1].Name(), "plugin")
+	byName, _ := simpledi.MapFrom[HealthCheck](c, "checks_by_name")
+	assertSameValue(t, byName["plugin"].Name(), "plugin")
+	server, _ := c.Get("server")
+	assertSameValue(t, server, any(4))
+	assertSameValue(t, c.Generation("server"), uint64(2))
+}
+
+func Test_Extend_Group_Rollback(t *testing.T) {
+	c := simpledi.New()
+	closed := make([]string, 0)
+	someError := errors.New("some error")
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "database",
+			Group: "checks",
+			New: func() any {
+				return healthCheck("database")
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "server",
+			Deps: []string{"checks"},
+			NewErr: func() (any, error) {
+				checks, _ := simpledi.GroupFrom[HealthCheck](c, "checks")
+				if len(checks) > 1 {
+					return nil, someError
+				}
+				return "server", nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error {
+		return c.Extend(simpledi.Definition{
+			ID:    "plugin",
+			Group: "checks",
+			New: func() any {
+				return healthCheck("plugin")
+			},
+			Close: func() error {
+				closed = append(closed, "plugin")
+				return nil
+			},
+		})
+	}, someError)
+	assertOrder(t, closed, []string{"plugin"})
+	assertSameValue(t, c.Has("plugin"), false)
+	checks, _ := simpledi.GroupFrom[HealthCheck](c, "checks")
+	assertSameValue(t, len(checks), 1)
+}
+
+func Test_Extend_Group_Registration_Order(t *testing.T) {
+	c := simpledi.New()
+	route := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:    id,
+			Deps:  deps,
+			Group: "routes",
+			New: func() any {
+				return id
+			},
+		}
+	}
+
+	assertNoError(t, func() error { return c.Set(route("x", "y")) })
+	assertNoError(t, func() error { return c.Set(route("y")) })
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "router",
+			Deps: []string{"routes"},
+			New: func() any {
+				routes, _ := simpledi.GroupFrom[string](c, "routes")
+				return routes
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertNoError(t, func() error {
+		return c.Extend(simpledi.Definition{
+			ID: "cache",
+			New: func() any {
+				return "cache"
+			},
+		})
+	})
+	assertSameValue(t, c.Generation("router"), uint64(1))
+
+	assertNoError(t, func() error { return c.Extend(route("z")) })
+	router, _ := simpledi.GetFrom[[]string](c, "router")
+	assertOrder(t, router, []string{"x", "y", "z"})
+	assertSameValue(t, c.Generation("router"), uint64(2))
+}
+
+func Test_Extend_Stats_After_Retry(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("some error")
+	fail := true
+
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	extend := func() error {
+		return c.Extend(simpledi.Definition{
+			ID: "cache",
+			NewErr: func() (any, error) {
+				if fail {
+					return nil, someError
+				}
+				return "cache", nil
+			},
+		})
+	}
+
+	assertError(t, extend, someError)
+	assertSameValue(t, len(c.Stats().Definitions), 0)
+	fail = false
+	assertNoError(t, extend)
+
+	definitions := c.Stats().Definitions
+	assertSameValue(t, len(definitions), 1)
+	assertSameValue(t, definitions[0].ID, "cache")
+}
@@ -0,0 +1,13 @@
+package simpledi
+
+// Graph returns the dependency IDs of every definition, keyed by ID.
+// After Resolve it also contains groups, maps and defined optional dependencies.
+func (c *Container) Graph() map[string][]string {
+	graph := make(map[string][]string, len(c.definitions))
+	for _, definition := range c.definitions {
+		deps := c.dependencies(definition)
+		graph[definition.ID] = append(make([]string, 0, len(deps)), deps...)
+	}
+
+	return graph
+}
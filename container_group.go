@@ -0,0 +1,72 @@
+package simpledi
+
+import "fmt"
+
+// groups returns a definition for every group that collects the instances of its members
+// in registration order. Groups of ignored definitions are kept, with no members if all are ignored.
+func (c *Container) groups(definitions, ignored []Definition) []Definition {
+	members := make(map[string][]string)
+	names := make([]string, 0)
+	for _, definition := range definitions {
+		if definition.Group == "" {
+			continue
+		}
+		if _, ok := members[definition.Group]; !ok {
+			names = append(names, definition.Group)
+		}
+		members[definition.Group] = append(members[definition.Group], definition.ID)
+	}
+	for _, definition := range ignored {
+		if definition.Group == "" {
+			continue
+		}
+		if _, ok := members[definition.Group]; !ok {
+			names = append(names, definition.Group)
+			members[definition.Group] = nil
+		}
+	}
+
+	groups := make([]Definition, 0, len(names))
+	for _, name := range names {
+		ids := members[name]
+		groups = append(groups, Definition{
+			ID:   name,
+			Deps: ids,
+			New: func() any {
+				instances := make([]any, len(ids))
+				for i, id := range ids {
+					instances[i], _ = c.lookup(id)
+				}
+				return instances
+			},
+			synthetic: true,
+		})
+	}
+
+	return groups
+}
+
+// GroupFrom returns the instances of a group by its name from the given container.
+// The error wraps ErrIDNotFound or ErrTypeMismatch.
+func GroupFrom[T any](c *Container, id string) ([]T, error) {
+	const op = "simpledi.GetGroup"
+
+	instances, err := GetFrom[[]any](c, id)
+	if err != nil {
+		return nil, err
+	}
+	typedInstances := make([]T, len(instances))
+	for i, instance := range instances {
+		if instance == nil {
+			continue
+		}
+		typedInstance, ok := instance.(T)
+		if !ok {
+			var zero T
+			return nil, fmt.Errorf("%s: %w (ID: %s, Index: %d, Want: %T, Got: %T)", op, ErrTypeMismatch, id, i, zero, instance)
+		}
+		typedInstances[i] = typedInstance
+	}
+
+	return typedInstances, nil
+}
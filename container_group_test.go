@@ -0,0 +1,143 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+type HealthCheck interface{ Name() string }
+
+type healthCheck string
+
+func (h healthCheck) Name() string { return string(h) }
+
+func Test_Group_Collects_In_Registration_Order(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+	check := func(id string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:    id,
+			Group: "health_checks",
+			New: func() any {
+				order = append(order, id)
+				return healthCheck(id)
+			},
+		}
+	}
+
+	assertNoError(t, func() error { return c.Set(check("database")) })
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "server",
+			Deps: []string{"health_checks"},
+			New: func() any {
+				checks, err := simpledi.GroupFrom[HealthCheck](c, "health_checks")
+				if err != nil {
+					t.Errorf("got: %v, want: no error", err)
+				}
+				names := make([]string, len(checks))
+				for i, check := range checks {
+					names[i] = check.Name()
+				}
+				order = append(order, "server")
+				return names
+			},
+		})
+	})
+	assertNoError(t, func() error { return c.Set(check("cache")) })
+	assertNoError(t, func() error { return c.Set(check("queue")) })
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertOrder(t, order, []string{"database", "cache", "queue", "server"})
+	names, err := simpledi.GetFrom[[]string](c, "server")
+	assertNoError(t, func() error { return err })
+	assertOrder(t, names, []string{"database", "cache", "queue"})
+}
+
+func Test_Group_Kept_Without_Members(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "database",
+			Group: "checks",
+			When:  []simpledi.Condition{simpledi.IfProfile("prod")},
+			New: func() any {
+				return healthCheck("database")
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "server",
+			Deps: []string{"checks"},
+			New: func() any {
+				checks, _ := simpledi.GroupFrom[HealthCheck](c, "checks")
+				return checks
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	checks, err := simpledi.GetFrom[[]HealthCheck](c, "server")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, len(checks), 0)
+}
+
+func Test_Group_Generic(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID:    "migration_1",
+			Group: "migrations",
+			New: func() any {
+				return "create users"
+			},
+		})
+		simpledi.Set(simpledi.Definition{
+			ID:    "migration_2",
+			Group: "migrations",
+			New: func() any {
+				return "create orders"
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	assertOrder(t, simpledi.GetGroup[string]("migrations"), []string{"create users", "create orders"})
+	assertPanic(t, func() {
+		_ = simpledi.GetGroup[int]("migrations")
+	}, simpledi.ErrTypeMismatch)
+	assertPanic(t, func() {
+		_ = simpledi.GetGroup[string]("routes")
+	}, simpledi.ErrIDNotFound)
+}
+
+func Test_Group_Err_ID_Duplicate(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:    "database",
+			Group: "checks",
+			New: func() any {
+				return "database"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "checks",
+			New: func() any {
+				return "checks"
+			},
+		})
+	})
+	assertError(t, c.Resolve, simpledi.ErrIDDuplicate)
+	assertError(t, c.Resolve, simpledi.ErrIDDuplicate)
+	assertNoError(t, c.Close)
+}
@@ -0,0 +1,84 @@
+package simpledi
+
+import "fmt"
+
+// maps returns a definition for every map that collects the instances of its members by key.
+// Maps of ignored definitions are kept, with no members if all are ignored.
+func (c *Container) maps(definitions, ignored []Definition) ([]Definition, error) {
+	const op = "simpledi.maps"
+
+	members := make(map[string]map[string]string)
+	deps := make(map[string][]string)
+	names := make([]string, 0)
+	for _, definition := range definitions {
+		if definition.Map == "" {
+			continue
+		}
+		if definition.Key == "" {
+			return nil, fmt.Errorf("%s: %w (%s, Map: %s)", op, ErrKeyRequired, definition.label(), definition.Map)
+		}
+		if _, ok := members[definition.Map]; !ok {
+			members[definition.Map] = make(map[string]string)
+			names = append(names, definition.Map)
+		}
+		if id, ok := members[definition.Map][definition.Key]; ok {
+			return nil, fmt.Errorf("%s: %w (%s, Map: %s, Key: %s, Previous: %s)", op, ErrKeyDuplicate, definition.label(), definition.Map, definition.Key, id)
+		}
+		members[definition.Map][definition.Key] = definition.ID
+		deps[definition.Map] = append(deps[definition.Map], definition.ID)
+	}
+	for _, definition := range ignored {
+		if definition.Map == "" {
+			continue
+		}
+		if _, ok := members[definition.Map]; !ok {
+			members[definition.Map] = make(map[string]string)
+			names = append(names, definition.Map)
+		}
+	}
+
+	maps := make([]Definition, 0, len(names))
+	for _, name := range names {
+		keys := members[name]
+		maps = append(maps, Definition{
+			ID:   name,
+			Deps: deps[name],
+			New: func() any {
+				instances := make(map[string]any, len(keys))
+				for key, id := range keys {
+					instances[key], _ = c.lookup(id)
+				}
+				return instances
+			},
+			synthetic: true,
+		})
+	}
+
+	return maps, nil
+}
+
+// MapFrom returns the instances of a map by its name from the given container.
+// The error wraps ErrIDNotFound or ErrTypeMismatch.
+func MapFrom[T any](c *Container, id string) (map[string]T, error) {
+	const op = "simpledi.GetMap"
+
+	instances, err := GetFrom[map[string]any](c, id)
+	if err != nil {
+		return nil, err
+	}
+	typedInstances := make(map[string]T, len(instances))
+	for key, instance := range instances {
+		var zero T
+		if instance == nil {
+			typedInstances[key] = zero
+			continue
+		}
+		typedInstance, ok := instance.(T)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w (ID: %s, Key: %s, Want: %T, Got: %T)", op, ErrTypeMismatch, id, key, zero, instance)
+		}
+		typedInstances[key] = typedInstance
+	}
+
+	return typedInstances, nil
+}
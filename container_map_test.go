@@ -0,0 +1,122 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Map_Collects_By_Key(t *testing.T) {
+	c := simpledi.New()
+	provider := func(id, key string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:  id,
+			Map: "payment_providers",
+			Key: key,
+			New: func() any {
+				return &ServiceImplB{data: key}
+			},
+		}
+	}
+
+	assertNoError(t, func() error { return c.Set(provider("stripe_provider", "stripe")) })
+	assertNoError(t, func() error { return c.Set(provider("paypal_provider", "paypal")) })
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "checkout",
+			Deps: []string{"payment_providers"},
+			New: func() any {
+				providers, err := simpledi.MapFrom[*ServiceImplB](c, "payment_providers")
+				if err != nil {
+					t.Errorf("got: %v, want: no error", err)
+				}
+				return len(providers)
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	providers, err := simpledi.MapFrom[*ServiceImplB](c, "payment_providers")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, len(providers), 2)
+	assertSameValue(t, providers["stripe"].data, "stripe")
+	assertSameValue(t, providers["paypal"].data, "paypal")
+	checkout, _ := c.Get("checkout")
+	assertSameValue(t, checkout, any(2))
+
+	_, err = simpledi.MapFrom[*ServiceImplA](c, "payment_providers")
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+}
+
+func Test_Map_Kept_Without_Members(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "stripe",
+			Map:  "payment_providers",
+			Key:  "stripe",
+			When: []simpledi.Condition{simpledi.IfProfile("prod")},
+			New: func() any {
+				return &ServiceImplB{data: "stripe"}
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "checkout",
+			Deps: []string{"payment_providers"},
+			New: func() any {
+				providers, _ := simpledi.MapFrom[*ServiceImplB](c, "payment_providers")
+				return providers
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	providers, err := simpledi.GetFrom[map[string]*ServiceImplB](c, "checkout")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, len(providers), 0)
+}
+
+func Test_Map_Generic(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID:  "stripe_provider",
+			Map: "payment_providers",
+			Key: "stripe",
+			New: func() any {
+				return "stripe"
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	assertSameValue(t, simpledi.GetMap[string]("payment_providers")["stripe"], "stripe")
+	assertPanic(t, func() {
+		_ = simpledi.GetMap[int]("payment_providers")
+	}, simpledi.ErrTypeMismatch)
+}
+
+func Test_Map_Err(t *testing.T) {
+	c := simpledi.New()
+
+	for _, d := range []simpledi.Definition{
+		{ID: "stripe_1", Map: "payment_providers", Key: "stripe", New: func() any { return 1 }},
+		{ID: "stripe_2", Map: "payment_providers", Key: "stripe", New: func() any { return 2 }},
+	} {
+		assertNoError(t, func() error { return c.Set(d) })
+	}
+	assertError(t, c.Resolve, simpledi.ErrKeyDuplicate)
+	assertNoError(t, c.Close)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{ID: "stripe", Map: "payment_providers", New: func() any { return 1 }})
+	})
+	assertError(t, c.Resolve, simpledi.ErrKeyRequired)
+	assertNoError(t, c.Close)
+}
@@ -0,0 +1,124 @@
+package simpledi
+
+import "fmt"
+
+// Module bundles related definitions into a reusable unit.
+type Module struct {
+	// Name is the name of the module. Required.
+	Name string
+	// Namespace qualifies the IDs of the module definitions as "Namespace.ID". Optional.
+	// Deps, Optional and Weak referring to an ID of the same module are qualified as well.
+	// Nested modules are qualified by their own Namespace.
+	Namespace string
+	// Definitions is the list of definitions of the module. Optional.
+	Definitions []Definition
+	// Modules is the list of nested modules. Optional.
+	Modules []Module
+}
+
+// Install adds all definitions of the module and its nested modules to the container.
+// Nothing is added if any definition is invalid.
+func (c *Container) Install(m Module) error {
+	const op = "simpledi.Install"
+
+	if c.resolved {
+		return fmt.Errorf("%s: %w", op, ErrContainerResolved)
+	}
+	definitions, err := m.flatten("", nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	c.register(definitions...)
+
+	return nil
+}
+
+// Modules returns the module path of every definition installed from a module, keyed by ID.
+// Nested module paths are joined with "/".
+func (c *Container) Modules() map[string]string {
+	modules := make(map[string]string)
+	for _, definition := range c.definitions {
+		if definition.module != "" {
+			modules[definition.ID] = definition.module
+		}
+	}
+
+	return modules
+}
+
+// ID returns the given ID qualified by the module namespace.
+func (m Module) ID(id string) string {
+	if m.Namespace == "" {
+		return id
+	}
+
+	return m.Namespace + "." + id
+}
+
+func (m Module) flatten(parent string, definitions []Definition) ([]Definition, error) {
+	if m.Name == "" {
+		if parent == "" {
+			return nil, ErrModuleNameRequired
+		}
+		return nil, fmt.Errorf("%w (Module: %s)", ErrModuleNameRequired, parent)
+	}
+	path := m.Name
+	if parent != "" {
+		path = parent + "/" + m.Name
+	}
+
+	own := make(map[string]bool, len(m.Definitions))
+	for _, d := range m.Definitions {
+		own[d.ID] = true
+	}
+	for _, d := range m.Definitions {
+		if d.ID == "" {
+			return nil, fmt.Errorf("%w (Module: %s)", ErrIDRequired, path)
+		}
+		if d.New == nil && d.NewErr == nil {
+			return nil, fmt.Errorf("%w (ID: %s, Module: %s)", ErrNewRequired, d.ID, path)
+		}
+		if m.Namespace != "" {
+			d.ID = m.ID(d.ID)
+			d.Deps = m.qualify(d.Deps, own)
+			d.Optional = m.qualify(d.Optional, own)
+			d.Weak = m.qualify(d.Weak, own)
+		}
+		d.module = path
+		d.namespace = m.Namespace
+		definitions = append(definitions, d)
+	}
+	for _, nested := range m.Modules {
+		var err error
+		definitions, err = nested.flatten(path, definitions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return definitions, nil
+}
+
+// qualify returns ids with the IDs of own definitions namespaced.
+func (m Module) qualify(ids []string, own map[string]bool) []string {
+	if ids == nil {
+		return nil
+	}
+	qualified := make([]string, len(ids))
+	for i, id := range ids {
+		if own[id] {
+			id = m.ID(id)
+		}
+		qualified[i] = id
+	}
+
+	return qualified
+}
+
+func (d Definition) label() string {
+	if d.module == "" {
+		return "ID: " + d.ID
+	}
+
+	return "ID: " + d.ID + ", Module: " + d.module
+}
@@ -0,0 +1,237 @@
+package simpledi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Install_Nested_Modules(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+	definition := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				order = append(order, id)
+				return id
+			},
+		}
+	}
+
+	assertNoError(t, func() error {
+		return c.Install(simpledi.Module{
+			Name:        "observability",
+			Definitions: []simpledi.Definition{definition("logger")},
+			Modules: []simpledi.Module{
+				{
+					Name:        "tracing",
+					Definitions: []simpledi.Definition{definition("tracer", "logger")},
+				},
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(definition("service", "tracer"))
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertOrder(t, order, []string{"logger", "tracer", "service"})
+
+	modules := c.Modules()
+	assertSameValue(t, len(modules), 2)
+	assertSameValue(t, modules["logger"], "observability")
+	assertSameValue(t, modules["tracer"], "observability/tracing")
+}
+
+func Test_Install_Err(t *testing.T) {
+	c := simpledi.New()
+
+	assertError(t, func() error {
+		return c.Install(simpledi.Module{})
+	}, simpledi.ErrModuleNameRequired)
+	assertError(t, func() error {
+		return c.Install(simpledi.Module{
+			Name:    "database",
+			Modules: []simpledi.Module{{}},
+		})
+	}, simpledi.ErrModuleNameRequired)
+
+	err := c.Install(simpledi.Module{
+		Name: "database",
+		Definitions: []simpledi.Definition{
+			{
+				ID: "client",
+				New: func() any {
+					return "client"
+				},
+			},
+			{
+				ID: "pool",
+			},
+		},
+	})
+	assertError(t, func() error { return err }, simpledi.ErrNewRequired)
+	assertSameValue(t, strings.Contains(err.Error(), "Module: database"), true)
+	assertSameValue(t, len(c.Modules()), 0)
+
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	assertError(t, func() error {
+		return c.Install(simpledi.Module{Name: "database"})
+	}, simpledi.ErrContainerResolved)
+}
+
+func Test_Install_Err_Qualified_By_Module(t *testing.T) {
+	defer simpledi.Close()
+
+	client := simpledi.Definition{
+		ID: "client",
+		New: func() any {
+			return "client"
+		},
+	}
+
+	assertNoPanic(t, func() {
+		simpledi.Install(simpledi.Module{
+			Name:        "database",
+			Definitions: []simpledi.Definition{client},
+		})
+		simpledi.Install(simpledi.Module{
+			Name:        "messaging",
+			Definitions: []simpledi.Definition{client},
+		})
+	})
+
+	defer func() {
+		err, _ := recover().(error)
+		assertError(t, func() error { return err }, simpledi.ErrIDDuplicate)
+		if err == nil || !strings.Contains(err.Error(), "ID: client, Module: messaging") {
+			t.Errorf("got: %v, want: module-qualified error", err)
+		}
+	}()
+	simpledi.Resolve()
+}
+
+func Test_Install_Namespace(t *testing.T) {
+	c := simpledi.New()
+	client := func(m *simpledi.Module) simpledi.Definition {
+		return simpledi.Definition{
+			ID: "client",
+			New: func() any {
+				return m.Namespace + " client"
+			},
+		}
+	}
+	database := simpledi.Module{Name: "database", Namespace: "db"}
+	database.Definitions = []simpledi.Definition{
+		client(&database),
+		{
+			ID:   "repository",
+			Deps: []string{"client"},
+			New: func() any {
+				client, _ := c.Get(database.ID("client"))
+				return "repository with " + client.(string)
+			},
+		},
+	}
+	messaging := simpledi.Module{Name: "messaging", Namespace: "mq"}
+	messaging.Definitions = []simpledi.Definition{client(&messaging)}
+
+	assertNoError(t, func() error { return c.Install(database) })
+	assertNoError(t, func() error { return c.Install(messaging) })
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	repository, _ := c.Get("db.repository")
+	assertSameValue(t, repository, any("repository with db client"))
+	mqClient, _ := c.Get("mq.client")
+	assertSameValue(t, mqClient, any("mq client"))
+	assertSameValue(t, c.Modules()["db.client"], "database")
+}
+
+func Test_Install_Namespace_Optional(t *testing.T) {
+	c := simpledi.New()
+	observability := simpledi.Module{Name: "observability", Namespace: "obs"}
+	observability.Definitions = []simpledi.Definition{
+		{
+			ID:       "exporter",
+			Optional: []string{"tracer"},
+			New: func() any {
+				if c.Has(observability.ID("tracer")) {
+					return "exporter with tracer"
+				}
+				return "exporter"
+			},
+		},
+		{
+			ID: "tracer",
+			New: func() any {
+				return "tracer"
+			},
+		},
+	}
+
+	assertNoError(t, func() error { return c.Install(observability) })
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	exporter, _ := c.Get("obs.exporter")
+	assertSameValue(t, exporter, any("exporter with tracer"))
+	assertOrder(t, c.Graph()["obs.exporter"], []string{"obs.tracer"})
+}
+
+func Test_Install_Private(t *testing.T) {
+	newDatabase := func() simpledi.Module {
+		return simpledi.Module{
+			Name:      "database",
+			Namespace: "db",
+			Definitions: []simpledi.Definition{
+				{
+					ID:      "pool",
+					Private: true,
+					New: func() any {
+						return "pool"
+					},
+				},
+				{
+					ID:   "client",
+					Deps: []string{"pool"},
+					New: func() any {
+						return "client"
+					},
+				},
+			},
+		}
+	}
+
+	c := simpledi.New()
+	assertNoError(t, func() error { return c.Install(newDatabase()) })
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service",
+			Deps: []string{"db.client"},
+			New: func() any {
+				return "service"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertNoError(t, c.Close)
+
+	assertNoError(t, func() error { return c.Install(newDatabase()) })
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service",
+			Deps: []string{"db.pool"},
+			New: func() any {
+				return "service"
+			},
+		})
+	})
+	assertError(t, c.Resolve, simpledi.ErrDependencyPrivate)
+	assertNoError(t, c.Close)
+}
@@ -0,0 +1,67 @@
+package simpledi
+
+import "time"
+
+// Observer holds callbacks invoked by the container on resolution events.
+// Every callback is optional.
+type Observer struct {
+	// BeforeNew is called before New of the definition.
+	BeforeNew func(id string)
+	// AfterNew is called after New of the definition with its duration.
+	// err is not nil if the instance could not be created.
+	AfterNew func(id string, d time.Duration, err error)
+	// BeforeClose is called before Close of the definition.
+	BeforeClose func(id string)
+	// AfterClose is called after Close of the definition with its duration and result.
+	AfterClose func(id string, d time.Duration, err error)
+	// OnGet is called on every Get with the requested ID.
+	OnGet func(id string)
+}
+
+// WithObserver registers an observer.
+// Observers are called in registration order.
+func WithObserver(o Observer) Option {
+	return func(c *Container) {
+		c.observers = append(c.observers, o)
+	}
+}
+
+func (c *Container) beforeNew(id string) {
+	for _, o := range c.observers {
+		if o.BeforeNew != nil {
+			o.BeforeNew(id)
+		}
+	}
+}
+
+func (c *Container) afterNew(id string, d time.Duration, err error) {
+	for _, o := range c.observers {
+		if o.AfterNew != nil {
+			o.AfterNew(id, d, err)
+		}
+	}
+}
+
+func (c *Container) beforeClose(id string) {
+	for _, o := range c.observers {
+		if o.BeforeClose != nil {
+			o.BeforeClose(id)
+		}
+	}
+}
+
+func (c *Container) afterClose(id string, d time.Duration, err error) {
+	for _, o := range c.observers {
+		if o.AfterClose != nil {
+			o.AfterClose(id, d, err)
+		}
+	}
+}
+
+func (c *Container) onGet(id string) {
+	for _, o := range c.observers {
+		if o.OnGet != nil {
+			o.OnGet(id)
+		}
+	}
+}
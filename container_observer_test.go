@@ -0,0 +1,104 @@
+package simpledi_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Observer_Events_Order(t *testing.T) {
+	events := make([]string, 0)
+	someError := errors.New("some error")
+	c := simpledi.New(simpledi.WithObserver(simpledi.Observer{
+		BeforeNew: func(id string) {
+			events = append(events, "before_new:"+id)
+		},
+		AfterNew: func(id string, _ time.Duration, err error) {
+			events = append(events, "after_new:"+id)
+			if err != nil {
+				t.Errorf("got: %v, want: no error", err)
+			}
+		},
+		BeforeClose: func(id string) {
+			events = append(events, "before_close:"+id)
+		},
+		AfterClose: func(id string, _ time.Duration, err error) {
+			events = append(events, "after_close:"+id)
+			if !errors.Is(err, someError) {
+				t.Errorf("got: %v, want: %v", err, someError)
+			}
+		},
+		OnGet: func(id string) {
+			events = append(events, "get:"+id)
+		},
+	}))
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+			Close: func() error {
+				return someError
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "bread",
+			Deps: []string{"yeast"},
+			New: func() any {
+				_, _ = c.Get("yeast")
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertError(t, c.Close, someError)
+
+	assertOrder(t, events, []string{
+		"before_new:yeast",
+		"after_new:yeast",
+		"before_new:bread",
+		"get:yeast",
+		"after_new:bread",
+		"before_close:yeast",
+		"after_close:yeast",
+	})
+}
+
+func Test_Observer_Multiple_Partial(t *testing.T) {
+	order := make([]string, 0)
+	c := simpledi.New(
+		simpledi.WithObserver(simpledi.Observer{
+			OnGet: func(id string) {
+				order = append(order, "first:"+id)
+			},
+		}),
+		simpledi.WithObserver(simpledi.Observer{
+			OnGet: func(id string) {
+				order = append(order, "second:"+id)
+			},
+		}),
+	)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertNoError(t, func() error {
+		_, err := c.Get("yeast")
+		return err
+	})
+	assertOrder(t, order, []string{"first:yeast", "second:yeast"})
+}
@@ -0,0 +1,26 @@
+package simpledi
+
+// Has reports whether an instance with the given ID exists.
+// It can be used in New to check whether an optional dependency was provided.
+func (c *Container) Has(id string) bool {
+	_, ok := c.lookup(id)
+
+	return ok
+}
+
+// present records which optional dependencies of every definition are defined.
+func (c *Container) present() {
+	ids := make(map[string]bool, len(c.definitions))
+	for _, definition := range c.definitions {
+		ids[definition.ID] = true
+	}
+	for i, definition := range c.definitions {
+		optional := make([]string, 0, len(definition.Optional))
+		for _, dependency := range definition.Optional {
+			if ids[dependency] {
+				optional = append(optional, dependency)
+			}
+		}
+		c.definitions[i].optional = optional
+	}
+}
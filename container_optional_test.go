@@ -0,0 +1,81 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Optional_Present(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:       "service",
+			Optional: []string{"tracer"},
+			New: func() any {
+				order = append(order, "service")
+				return c.Has("tracer")
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "tracer",
+			New: func() any {
+				order = append(order, "tracer")
+				return "tracer"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertOrder(t, order, []string{"tracer", "service"})
+	traced, _ := c.Get("service")
+	assertSameValue(t, traced, any(true))
+}
+
+func Test_Optional_Absent(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID:       "service",
+			Optional: []string{"tracer"},
+			New: func() any {
+				return simpledi.Has("tracer")
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	assertSameValue(t, simpledi.Get[bool]("service"), false)
+	assertSameValue(t, simpledi.Has("service"), true)
+}
+
+func Test_Optional_Err_Dependency_Cycle(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:       "service_1",
+			Optional: []string{"service_2"},
+			New: func() any {
+				return "service_1"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service_2",
+			Deps: []string{"service_1"},
+			New: func() any {
+				return "service_2"
+			},
+		})
+	})
+	assertError(t, c.Resolve, simpledi.ErrDependencyCycle)
+	assertNoError(t, c.Close)
+}
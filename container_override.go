@@ -0,0 +1,54 @@
+package simpledi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Override replaces existing definitions with the same ID.
+// The replaced definitions are recorded and returned by Overridden.
+//
+// On a resolved container the definition is swapped in the same way as by Replace.
+// Returns a combined error if any Close calls fail.
+func (c *Container) Override(d Definition) error {
+	const op = "simpledi.Override"
+
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
+	if d.ID == "" {
+		return fmt.Errorf("%s: %w", op, ErrIDRequired)
+	}
+	if d.New == nil && d.NewErr == nil {
+		return fmt.Errorf("%s: %w (ID: %s)", op, ErrNewRequired, d.ID)
+	}
+	previous := c.definitions
+	definitions, replaced := replace(previous, d)
+	if len(replaced) == 0 {
+		return fmt.Errorf("%s: %w (ID: %s)", op, ErrIDNotFound, d.ID)
+	}
+	c.definitions = definitions
+	c.overridden = append(c.overridden, replaced...)
+	if !c.resolved {
+		return nil
+	}
+
+	ctx, end := c.traceTask(context.Background(), op)
+	defer end()
+	if swapped, err := c.rebuild(ctx, previous, d.ID); err != nil {
+		if swapped == nil {
+			c.overridden = c.overridden[:len(c.overridden)-len(replaced)]
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Overridden returns the definitions replaced by Override, in override order.
+func (c *Container) Overridden() []Definition {
+	overridden := make([]Definition, len(c.overridden))
+	copy(overridden, c.overridden)
+
+	return overridden
+}
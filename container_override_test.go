@@ -0,0 +1,174 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Override_Replaces_Definition(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+		simpledi.Set(simpledi.Definition{
+			ID:   "service",
+			Deps: []string{"database"},
+			New: func() any {
+				return "service with " + simpledi.Get[string]("database")
+			},
+		})
+		simpledi.Override(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "fake"
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	assertSameValue(t, simpledi.Get[string]("database"), "fake")
+	assertSameValue(t, simpledi.Get[string]("service"), "service with fake")
+}
+
+func Test_Override_Overridden(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Override(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "fake"
+			},
+		})
+	})
+
+	overridden := c.Overridden()
+	assertSameValue(t, len(overridden), 1)
+	assertSameValue(t, overridden[0].ID, "database")
+	assertSameValue(t, overridden[0].New(), any("postgres"))
+
+	assertNoError(t, c.Close)
+	assertSameValue(t, len(c.Overridden()), 0)
+}
+
+func Test_Override_Err(t *testing.T) {
+	defer simpledi.Close()
+
+	assertPanic(t, func() {
+		simpledi.Override(simpledi.Definition{
+			New: func() any {
+				return "fake"
+			},
+		})
+	}, simpledi.ErrIDRequired)
+	assertPanic(t, func() {
+		simpledi.Override(simpledi.Definition{
+			ID: "database",
+		})
+	}, simpledi.ErrNewRequired)
+	assertPanic(t, func() {
+		simpledi.Override(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "fake"
+			},
+		})
+	}, simpledi.ErrIDNotFound)
+}
+
+func Test_Override_Resolved_Rebuilds_Dependents(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+	definition := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				order = append(order, "new:"+id)
+				return id
+			},
+			Close: func() error {
+				order = append(order, "close:"+id)
+				return nil
+			},
+		}
+	}
+
+	for _, d := range []simpledi.Definition{
+		definition("config"),
+		definition("database", "config"),
+		definition("cache", "config"),
+		definition("service", "database", "cache"),
+	} {
+		assertNoError(t, func() error { return c.Set(d) })
+	}
+	assertNoError(t, c.Resolve)
+	order = order[:0]
+
+	fake := definition("database", "config")
+	fake.New = func() any {
+		order = append(order, "new:fake")
+		return "fake"
+	}
+	assertNoError(t, func() error { return c.Override(fake) })
+
+	assertOrder(t, order, []string{"new:fake", "new:service", "close:service", "close:database"})
+	database, _ := c.Get("database")
+	assertSameValue(t, database, any("fake"))
+
+	order = order[:0]
+	assertNoError(t, c.Close)
+	assertOrder(t, order, []string{"close:service", "close:cache", "close:database", "close:config"})
+}
+
+func Test_Override_Resolved_Err_Dependency_Cycle(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service",
+			Deps: []string{"database"},
+			New: func() any {
+				return "service"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error {
+		return c.Override(simpledi.Definition{
+			ID:   "database",
+			Deps: []string{"service"},
+			New: func() any {
+				return "fake"
+			},
+		})
+	}, simpledi.ErrDependencyCycle)
+
+	database, _ := c.Get("database")
+	assertSameValue(t, database, any("postgres"))
+	assertSameValue(t, len(c.Overridden()), 0)
+}
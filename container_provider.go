@@ -0,0 +1,39 @@
+package simpledi
+
+import "slices"
+
+// Provider gives deferred access to an instance of type T.
+type Provider[T any] struct {
+	c  *Container
+	id string
+}
+
+// ProviderOf returns a Provider of the instance with the given ID in the given container.
+// The instance may not be created yet when ProviderOf is called from New, so Get should be called later.
+// List the ID in Weak of the definition to report the reference in WeakGraph.
+func ProviderOf[T any](c *Container, id string) Provider[T] {
+	return Provider[T]{c: c, id: id}
+}
+
+// ID returns the ID of the provided instance.
+func (p Provider[T]) ID() string {
+	return p.id
+}
+
+// Get returns the current instance.
+// The error wraps ErrIDNotFound or ErrTypeMismatch.
+func (p Provider[T]) Get() (T, error) {
+	return GetFrom[T](p.c, p.id)
+}
+
+// WeakGraph returns the Weak IDs of every definition that has any, keyed by ID.
+func (c *Container) WeakGraph() map[string][]string {
+	graph := make(map[string][]string)
+	for _, definition := range c.definitions {
+		if len(definition.Weak) > 0 {
+			graph[definition.ID] = slices.Clone(definition.Weak)
+		}
+	}
+
+	return graph
+}
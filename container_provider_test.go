@@ -0,0 +1,100 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+type Node struct {
+	name string
+	peer simpledi.Provider[*Node]
+}
+
+func Test_Provider_Two_Way_Reference(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "client",
+			Weak: []string{"server"},
+			New: func() any {
+				return &Node{name: "client", peer: simpledi.ProviderOf[*Node](c, "server")}
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "server",
+			Deps: []string{"client"},
+			Weak: []string{"client"},
+			New: func() any {
+				return &Node{name: "server", peer: simpledi.ProviderOf[*Node](c, "client")}
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	client, _ := simpledi.GetFrom[*Node](c, "client")
+	server, err := client.peer.Get()
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, server.name, "server")
+	peer, _ := server.peer.Get()
+	assertSamePointer(t, peer, client)
+
+	weak := c.WeakGraph()
+	assertOrder(t, weak["client"], []string{"server"})
+	assertOrder(t, weak["server"], []string{"client"})
+	assertOrder(t, c.Graph()["client"], []string{})
+}
+
+func Test_Provider_Follows_Replace(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	provider := simpledi.ProviderOf[string](c, "database")
+	assertSameValue(t, provider.ID(), "database")
+	assertSameValue(t, len(c.WeakGraph()), 0)
+	assertNoError(t, func() error {
+		return c.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "sqlite"
+			},
+		})
+	})
+
+	database, err := provider.Get()
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, database, "sqlite")
+}
+
+func Test_Provider_Err(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	_, err := simpledi.ProviderOf[string](c, "cache").Get()
+	assertError(t, func() error { return err }, simpledi.ErrIDNotFound)
+	_, err = simpledi.ProviderOf[int](c, "database").Get()
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+}
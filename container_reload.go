@@ -0,0 +1,84 @@
+package simpledi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Reload creates the instances of Reloadable definitions and of all their dependents again,
+// in the same way as Replace. The rebuilt IDs are logged if WithLogger is set.
+func (c *Container) Reload() error {
+	const op = "simpledi.Reload"
+
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
+	if !c.resolved {
+		return fmt.Errorf("%s: %w", op, ErrContainerNotResolved)
+	}
+	ids := make([]string, 0)
+	for _, definition := range c.definitions {
+		if definition.Reloadable {
+			ids = append(ids, definition.ID)
+		}
+	}
+
+	start := time.Now()
+	ctx, end := c.traceTask(context.Background(), op)
+	defer end()
+	rebuilt, err := c.rebuild(ctx, c.definitions, ids...)
+	c.logReload(rebuilt, time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReloadOn calls Reload on every value received from trigger
+// until ctx is done or trigger is closed.
+// Reload errors are passed to onErr if it is not nil, and logged if WithLogger is set.
+func (c *Container) ReloadOn(ctx context.Context, trigger <-chan struct{}, onErr func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-trigger:
+			if !ok {
+				return
+			}
+			c.reload(onErr)
+		}
+	}
+}
+
+// ReloadOnSignal calls Reload on every received signal until ctx is done.
+// Listens for SIGHUP if no signals are given.
+// Reload errors are passed to onErr if it is not nil, and logged if WithLogger is set.
+func (c *Container) ReloadOnSignal(ctx context.Context, onErr func(error), sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+	defer signal.Stop(received)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-received:
+			c.reload(onErr)
+		}
+	}
+}
+
+func (c *Container) reload(onErr func(error)) {
+	if err := c.Reload(); err != nil && onErr != nil {
+		onErr(err)
+	}
+}
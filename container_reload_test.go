@@ -0,0 +1,189 @@
+package simpledi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Reload_Rebuilds_Reloadable(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	c := simpledi.New(simpledi.WithLogger(logger, slog.LevelInfo))
+	order := make([]string, 0)
+	version := 0
+	definition := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				order = append(order, "new:"+id)
+				return id
+			},
+			Close: func() error {
+				order = append(order, "close:"+id)
+				return nil
+			},
+		}
+	}
+
+	credentials := definition("credentials")
+	credentials.Reloadable = true
+	credentials.New = func() any {
+		version++
+		order = append(order, "new:credentials")
+		return version
+	}
+	assertNoError(t, func() error { return c.Set(definition("config")) })
+	assertNoError(t, func() error { return c.Set(credentials) })
+	assertNoError(t, func() error { return c.Set(definition("database", "config", "credentials")) })
+	assertNoError(t, func() error { return c.Set(definition("cache", "config")) })
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	order = order[:0]
+
+	assertNoError(t, c.Reload)
+	assertOrder(t, order, []string{"new:credentials", "new:database", "close:database", "close:credentials"})
+	got, _ := c.Get("credentials")
+	assertSameValue(t, got, any(2))
+	if !strings.Contains(buf.String(), `msg="simpledi: reloaded" ids="[credentials database]"`) {
+		t.Errorf("got: %s, want: reloaded record", buf.String())
+	}
+}
+
+func Test_Reload_On_Trigger(t *testing.T) {
+	c := simpledi.New()
+	var version atomic.Int64
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:         "credentials",
+			Reloadable: true,
+			New: func() any {
+				return version.Add(1)
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	trigger := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.ReloadOn(context.Background(), trigger, nil)
+	}()
+	trigger <- struct{}{}
+	trigger <- struct{}{}
+	close(trigger)
+	<-done
+
+	got, _ := c.Get("credentials")
+	assertSameValue(t, got, any(int64(3)))
+}
+
+func Test_Reload_On_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported")
+	}
+	c := simpledi.New()
+	reloaded := make(chan struct{}, 1)
+	count := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:         "credentials",
+			Reloadable: true,
+			New: func() any {
+				count++
+				if count > 1 {
+					reloaded <- struct{}{}
+				}
+				return count
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, syscall.SIGHUP)
+	defer signal.Stop(received)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.ReloadOnSignal(ctx, nil)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	process, _ := os.FindProcess(os.Getpid())
+	deadline := time.After(5 * time.Second)
+	for {
+		assertNoError(t, func() error { return process.Signal(syscall.SIGHUP) })
+		select {
+		case <-reloaded:
+			return
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("got: no reload, want: reload")
+		}
+	}
+}
+
+func Test_Reload_On_Trigger_Err(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("some error")
+	attempts := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:         "credentials",
+			Reloadable: true,
+			NewErr: func() (any, error) {
+				attempts++
+				if attempts > 1 {
+					return nil, someError
+				}
+				return "credentials", nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	trigger := make(chan struct{}, 1)
+	trigger <- struct{}{}
+	close(trigger)
+	errs := make([]error, 0)
+	c.ReloadOn(context.Background(), trigger, func(err error) {
+		errs = append(errs, err)
+	})
+
+	assertSameValue(t, len(errs), 1)
+	assertError(t, func() error { return errs[0] }, someError)
+	credentials, _ := c.Get("credentials")
+	assertSameValue(t, credentials, any("credentials"))
+}
+
+func Test_Reload_Err(t *testing.T) {
+	c := simpledi.New()
+
+	assertError(t, c.Reload, simpledi.ErrContainerNotResolved)
+	assertPanic(t, simpledi.Reload, simpledi.ErrContainerNotResolved)
+}
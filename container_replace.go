@@ -0,0 +1,175 @@
+package simpledi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Replace swaps the definition with the given ID on a resolved container.
+// The new instance and instances of all its transitive dependents are created in topological order
+// and swapped in together once all of them succeed.
+// Old instances are then closed in reverse order, unless they are captured by a Snapshot.
+// If any of them fails, the created instances are closed and the previous definition is kept.
+// If the definition was installed from a module, Deps, Optional and Weak referring to an ID of that module
+// are qualified by its namespace, as by Install.
+//
+// Get is not isolated from Replace: from any goroutine, it returns the new instances created so far
+// as soon as they are created, even if Replace later fails and closes them,
+// and an old instance while its Close runs.
+func (c *Container) Replace(id string, d Definition) error {
+	const op = "simpledi.Replace"
+
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
+	if !c.resolved {
+		return fmt.Errorf("%s: %w", op, ErrContainerNotResolved)
+	}
+	if id == "" {
+		return fmt.Errorf("%s: %w", op, ErrIDRequired)
+	}
+	if d.New == nil && d.NewErr == nil {
+		return fmt.Errorf("%s: %w (ID: %s)", op, ErrNewRequired, id)
+	}
+	d.ID = id
+	previous := c.definitions
+	definitions, replaced := replace(previous, d)
+	if len(replaced) == 0 {
+		return fmt.Errorf("%s: %w (ID: %s)", op, ErrIDNotFound, id)
+	}
+	c.definitions = definitions
+
+	ctx, end := c.traceTask(context.Background(), op)
+	defer end()
+	if _, err := c.rebuild(ctx, previous, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// replace returns definitions with the first definition with the ID of d replaced by d
+// and the others dropped, along with the replaced definitions.
+// d takes the module of the first replaced definition, and its Deps, Optional and Weak
+// referring to an ID of that module are qualified by the module namespace, as by Install.
+func replace(definitions []Definition, d Definition) ([]Definition, []Definition) {
+	result := make([]Definition, 0, len(definitions))
+	replaced := make([]Definition, 0)
+	for _, definition := range definitions {
+		if definition.ID != d.ID {
+			result = append(result, definition)
+			continue
+		}
+		if len(replaced) == 0 {
+			d.seq = definition.seq
+			d.module = definition.module
+			d.namespace = definition.namespace
+			if d.namespace != "" {
+				m := Module{Namespace: d.namespace}
+				own := make(map[string]bool)
+				for _, other := range definitions {
+					if other.module == d.module {
+						own[strings.TrimPrefix(other.ID, d.namespace+".")] = true
+					}
+				}
+				d.Deps = m.qualify(d.Deps, own)
+				d.Optional = m.qualify(d.Optional, own)
+				d.Weak = m.qualify(d.Weak, own)
+			}
+			result = append(result, d)
+		}
+		replaced = append(replaced, definition)
+	}
+
+	return result, replaced
+}
+
+// rebuild creates the built instances of the definitions with the given IDs and of their dependents again,
+// after c.definitions were changed from previous.
+// New instances are swapped in only if all of them are created,
+// otherwise they are closed and previous definitions are restored.
+// Returns the IDs of the swapped in instances, or nil if they were not swapped in.
+func (c *Container) rebuild(ctx context.Context, previous []Definition, ids ...string) ([]string, error) {
+	if err := c.sort(); err != nil {
+		c.definitions = previous
+		return nil, err
+	}
+
+	affected := make(map[string]bool)
+	for _, id := range ids {
+		for dependent := range c.dependents(id) {
+			affected[dependent] = true
+		}
+	}
+	rebuilt := make([]Definition, 0, len(affected))
+	for _, definition := range c.definitions {
+		if affected[definition.ID] && c.built(definition.ID) {
+			rebuilt = append(rebuilt, definition)
+		}
+	}
+
+	staging := make(map[string]any, len(rebuilt))
+	c.mu.Lock()
+	c.staging = staging
+	c.mu.Unlock()
+	for i, definition := range rebuilt {
+		if err := c.newInstance(ctx, definition); err != nil {
+			err = errors.Join(err, c.rollback(ctx, rebuilt[:i]))
+			c.mu.Lock()
+			c.staging = nil
+			c.mu.Unlock()
+			c.definitions = previous
+			return nil, err
+		}
+	}
+
+	swapped := make([]string, 0, len(rebuilt))
+	events := make([]Event, 0, len(rebuilt))
+	old := make(map[string]retiredInstance, len(rebuilt))
+	c.mu.Lock()
+	for _, definition := range rebuilt {
+		swapped = append(swapped, definition.ID)
+		old[definition.ID] = retiredInstance{
+			instance:   c.instances[definition.ID],
+			build:      c.builds[definition.ID],
+			generation: c.generations[definition.ID],
+		}
+		c.instances[definition.ID] = c.staging[definition.ID]
+		c.serial++
+		c.builds[definition.ID] = c.serial
+		c.generations[definition.ID]++
+		events = append(events, Event{
+			ID:         definition.ID,
+			Kind:       EventRebuilt,
+			Generation: c.generations[definition.ID],
+		})
+	}
+	c.staging = nil
+	c.mu.Unlock()
+	for _, event := range events {
+		c.notify(event)
+	}
+
+	errs := make([]error, 0)
+	for i := len(previous) - 1; i >= 0; i-- {
+		definition := previous[i]
+		superseded, ok := old[definition.ID]
+		if !ok {
+			continue
+		}
+		superseded.definition = definition
+		if c.pinned[superseded.build] {
+			c.retired = append(c.retired, superseded)
+			continue
+		}
+		if definition.Close != nil {
+			if err := c.closeSuperseded(ctx, superseded); err != nil {
+				errs = append(errs, fmt.Errorf("%w (%s)", err, definition.label()))
+			}
+		}
+	}
+
+	return swapped, errors.Join(errs...)
+}
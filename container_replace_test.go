@@ -0,0 +1,258 @@
+package simpledi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Replace_Rebuilds_Dependents(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+	closedWith := make([]any, 0)
+	definition := func(id, value string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				order = append(order, "new:"+value)
+				return value
+			},
+			Close: func() error {
+				order = append(order, "close:"+id)
+				instance, _ := c.Get(id)
+				closedWith = append(closedWith, instance)
+				return nil
+			},
+		}
+	}
+
+	assertNoError(t, func() error { return c.Set(definition("config", "config")) })
+	assertNoError(t, func() error { return c.Set(definition("database", "postgres", "config")) })
+	assertNoError(t, func() error { return c.Set(definition("cache", "cache", "config")) })
+	assertNoError(t, func() error { return c.Set(definition("service", "service", "database")) })
+	assertNoError(t, c.Resolve)
+	order = order[:0]
+
+	assertNoError(t, func() error {
+		return c.Replace("database", definition("database", "sqlite", "config"))
+	})
+	assertOrder(t, order, []string{"new:sqlite", "new:service", "close:service", "close:database"})
+	assertSameValue(t, closedWith[1], any("postgres"))
+
+	database, _ := c.Get("database")
+	assertSameValue(t, database, any("sqlite"))
+
+	order = order[:0]
+	assertNoError(t, c.Close)
+	assertOrder(t, order, []string{"close:service", "close:cache", "close:database", "close:config"})
+	assertSameValue(t, closedWith[len(closedWith)-2], any("sqlite"))
+}
+
+func Test_Replace_Rollback(t *testing.T) {
+	c := simpledi.New()
+	closed := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return &ServiceImplA{}
+			},
+		})
+	})
+	assertNoError(t, func() error { return simpledi.BindTo[ServiceA](c, "service", "database") })
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error {
+		return c.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "fake"
+			},
+			Close: func() error {
+				closed = append(closed, "fake")
+				return nil
+			},
+		})
+	}, simpledi.ErrTypeMismatch)
+	assertOrder(t, closed, []string{"fake"})
+
+	database, _ := c.Get("database")
+	_, ok := database.(*ServiceImplA)
+	assertSameValue(t, ok, true)
+	service, _ := c.Get("service")
+	assertSameValue(t, service, database)
+}
+
+func Test_Replace_Generic(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+		simpledi.Resolve()
+		simpledi.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "sqlite"
+			},
+		})
+	})
+
+	assertSameValue(t, simpledi.Get[string]("database"), "sqlite")
+}
+
+func Test_Replace_Namespace(t *testing.T) {
+	c := simpledi.New()
+	database := simpledi.Module{
+		Name:      "database",
+		Namespace: "db",
+		Definitions: []simpledi.Definition{
+			{
+				ID: "cfg",
+				New: func() any {
+					return "postgres://localhost"
+				},
+			},
+			{
+				ID: "client",
+				New: func() any {
+					return "client"
+				},
+			},
+		},
+	}
+
+	assertNoError(t, func() error { return c.Install(database) })
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	assertNoError(t, func() error {
+		return c.Replace("db.client", simpledi.Definition{
+			Deps: []string{"cfg"},
+			New: func() any {
+				cfg, _ := c.Get("db.cfg")
+				return "client for " + cfg.(string)
+			},
+		})
+	})
+
+	client, _ := c.Get("db.client")
+	assertSameValue(t, client, any("client for postgres://localhost"))
+	assertOrder(t, c.Graph()["db.client"], []string{"db.cfg"})
+}
+
+func Test_Replace_Err(t *testing.T) {
+	c := simpledi.New()
+	fake := simpledi.Definition{
+		New: func() any {
+			return "fake"
+		},
+	}
+
+	assertError(t, func() error { return c.Replace("database", fake) }, simpledi.ErrContainerNotResolved)
+
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error { return c.Replace("", fake) }, simpledi.ErrIDRequired)
+	assertError(t, func() error {
+		return c.Replace("database", simpledi.Definition{})
+	}, simpledi.ErrNewRequired)
+	assertError(t, func() error { return c.Replace("database", fake) }, simpledi.ErrIDNotFound)
+}
+
+func Test_Replace_Concurrent_Get_Sees_Staged(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("some error")
+	get := func(id string) any {
+		got := make(chan any)
+		go func() {
+			instance, _ := c.Get(id)
+			got <- instance
+		}()
+		return <-got
+	}
+	seen := make([]any, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "v1"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "service",
+			Deps: []string{"config"},
+			NewErr: func() (any, error) {
+				config, _ := c.Get("config")
+				if config == "v1" {
+					return "service", nil
+				}
+				seen = append(seen, config, get("config"))
+				return nil, someError
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertError(t, func() error {
+		return c.Replace("config", simpledi.Definition{
+			New: func() any {
+				return "v2"
+			},
+			Close: func() error {
+				config, _ := c.Get("config")
+				seen = append(seen, config, get("config"))
+				return nil
+			},
+		})
+	}, someError)
+	assertOrder(t, seen, []any{"v2", "v2", "v2", "v2"})
+
+	config, _ := c.Get("config")
+	assertSameValue(t, config, any("v1"))
+}
+
+func Test_Replace_Concurrent_Get_During_Close(t *testing.T) {
+	c := simpledi.New()
+	seen := make([]any, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "v1"
+			},
+			Close: func() error {
+				config, _ := c.Get("config")
+				got := make(chan any)
+				go func() {
+					instance, _ := c.Get("config")
+					got <- instance
+				}()
+				seen = append(seen, config, <-got)
+				return nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertNoError(t, func() error {
+		return c.Replace("config", simpledi.Definition{
+			New: func() any {
+				return "v2"
+			},
+		})
+	})
+	assertOrder(t, seen, []any{"v1", "v1"})
+}
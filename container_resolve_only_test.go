@@ -0,0 +1,87 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_ResolveOnly_Builds_Closure(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+	closed := make([]string, 0)
+	definition := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				order = append(order, id)
+				return id
+			},
+			Close: func() error {
+				closed = append(closed, id)
+				return nil
+			},
+		}
+	}
+
+	for _, d := range []simpledi.Definition{
+		definition("config"),
+		definition("database", "config"),
+		definition("cache", "config"),
+		definition("migrate", "database"),
+		definition("server", "database", "cache"),
+	} {
+		assertNoError(t, func() error { return c.Set(d) })
+	}
+	assertNoError(t, func() error { return c.ResolveOnly("migrate") })
+
+	assertOrder(t, order, []string{"config", "database", "migrate"})
+	assertSameValue(t, c.Has("server"), false)
+	assertSameValue(t, c.Has("cache"), false)
+	assertError(t, c.Resolve, simpledi.ErrContainerResolved)
+
+	assertNoError(t, c.Close)
+	assertOrder(t, closed, []string{"migrate", "database", "config"})
+}
+
+func Test_ResolveOnly_Validates_Whole_Graph(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "config"
+			},
+		})
+		simpledi.Set(simpledi.Definition{
+			ID:   "server",
+			Deps: []string{"database"},
+			New: func() any {
+				return "server"
+			},
+		})
+	})
+
+	assertPanic(t, func() {
+		simpledi.ResolveOnly("config")
+	}, simpledi.ErrDependencyNotFound)
+}
+
+func Test_ResolveOnly_Err(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "config",
+			New: func() any {
+				return "config"
+			},
+		})
+	})
+	assertError(t, func() error { return c.ResolveOnly() }, simpledi.ErrIDRequired)
+	assertError(t, func() error { return c.ResolveOnly("server") }, simpledi.ErrIDNotFound)
+	assertNoError(t, func() error { return c.ResolveOnly("config") })
+	assertNoError(t, c.Close)
+}
@@ -0,0 +1,72 @@
+package simpledi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy describes how NewErr is called again after a failure.
+// The zero value makes a single attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, including the first one.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts. Optional.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt. Defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of every delay, from 0 to 1, that is randomly taken off.
+	Jitter float64
+	// Retryable reports whether a failed call may be retried. Optional, all errors are retried by default.
+	Retryable func(err error) bool
+}
+
+// run calls fn until it succeeds, the attempts run out, the error is not retryable or ctx is done.
+// If more than one attempt is allowed, the error joins the errors of every attempt.
+func (p RetryPolicy) run(ctx context.Context, fn func() (any, error)) (any, error) {
+	if p.MaxAttempts <= 1 {
+		return fn()
+	}
+
+	errs := make([]error, 0, p.MaxAttempts)
+	for attempt := 1; ; attempt++ {
+		instance, err := fn()
+		if err == nil {
+			return instance, nil
+		}
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+		if attempt == p.MaxAttempts || (p.Retryable != nil && !p.Retryable(err)) {
+			return nil, errors.Join(errs...)
+		}
+
+		timer := time.NewTimer(p.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(append(errs, ctx.Err())...)
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the delay after the given attempt.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	delay := float64(p.Backoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		delay = min(delay, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
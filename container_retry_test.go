@@ -0,0 +1,125 @@
+package simpledi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Retry_Succeeds(t *testing.T) {
+	c := simpledi.New()
+	attempts := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			NewErr: func() (any, error) {
+				attempts++
+				if attempts < 3 {
+					return nil, errors.New("connection refused")
+				}
+				return "postgres", nil
+			},
+			Retry: simpledi.RetryPolicy{
+				MaxAttempts: 5,
+				Backoff:     time.Millisecond,
+				Jitter:      0.5,
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertSameValue(t, attempts, 3)
+	database, _ := c.Get("database")
+	assertSameValue(t, database, any("postgres"))
+}
+
+func Test_Retry_Err_Attempts(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("connection refused")
+	attempts := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			NewErr: func() (any, error) {
+				attempts++
+				return nil, someError
+			},
+			Retry: simpledi.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+				MaxBackoff:  2 * time.Millisecond,
+			},
+		})
+	})
+
+	err := c.Resolve()
+	assertError(t, func() error { return err }, someError)
+	assertSameValue(t, attempts, 3)
+	for _, want := range []string{"attempt 1: ", "attempt 2: ", "attempt 3: ", "ID: database"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got: %q, want: %q", err, want)
+		}
+	}
+	assertNoError(t, c.Close)
+}
+
+func Test_Retry_Not_Retryable(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("bad password")
+	attempts := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			NewErr: func() (any, error) {
+				attempts++
+				return nil, someError
+			},
+			Retry: simpledi.RetryPolicy{
+				MaxAttempts: 3,
+				Retryable: func(err error) bool {
+					return !errors.Is(err, someError)
+				},
+			},
+		})
+	})
+
+	assertError(t, c.Resolve, someError)
+	assertSameValue(t, attempts, 1)
+	assertNoError(t, c.Close)
+}
+
+func Test_Retry_Context_Canceled(t *testing.T) {
+	c := simpledi.New()
+	someError := errors.New("connection refused")
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			NewErr: func() (any, error) {
+				attempts++
+				cancel()
+				return nil, someError
+			},
+			Retry: simpledi.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Hour,
+			},
+		})
+	})
+
+	err := c.ResolveContext(ctx)
+	assertError(t, func() error { return err }, context.Canceled)
+	assertError(t, func() error { return err }, someError)
+	assertSameValue(t, attempts, 1)
+	assertNoError(t, c.Close)
+}
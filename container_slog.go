@@ -0,0 +1,59 @@
+package simpledi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger emits a log/slog record for every created and closed instance.
+// Records are logged at the given level, failures at slog.LevelError.
+func WithLogger(logger *slog.Logger, level slog.Level) Option {
+	return func(c *Container) {
+		c.logger = logger
+		c.logLevel = level
+	}
+}
+
+func (c *Container) logNew(definition Definition, deps []string, d time.Duration, err error) {
+	c.log("simpledi: instance created", definition, deps, d, err)
+}
+
+func (c *Container) logClose(definition Definition, d time.Duration, err error) {
+	c.log("simpledi: instance closed", definition, c.dependencies(definition), d, err)
+}
+
+func (c *Container) logReload(ids []string, d time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	level := c.logLevel
+	attrs := []slog.Attr{
+		slog.Any("ids", ids),
+		slog.Duration("duration", d),
+	}
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	c.logger.LogAttrs(context.Background(), level, "simpledi: reloaded", attrs...)
+}
+
+func (c *Container) log(msg string, definition Definition, deps []string, d time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	level := c.logLevel
+	attrs := []slog.Attr{
+		slog.String("id", definition.ID),
+		slog.Any("deps", deps),
+		slog.Duration("duration", d),
+	}
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	c.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
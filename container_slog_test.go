@@ -0,0 +1,137 @@
+package simpledi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Logger_Records(t *testing.T) {
+	var buf bytes.Buffer
+	someError := errors.New("some error")
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := simpledi.New(simpledi.WithLogger(logger, slog.LevelDebug))
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+			Close: func() error {
+				return someError
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "bread",
+			Deps: []string{"yeast"},
+			New: func() any {
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertError(t, c.Close, someError)
+
+	type record struct {
+		Level string   `json:"level"`
+		Msg   string   `json:"msg"`
+		ID    string   `json:"id"`
+		Deps  []string `json:"deps"`
+		Error string   `json:"error"`
+	}
+	records := make([]record, 0)
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var r record
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("got: %v, want: no error", err)
+		}
+		records = append(records, r)
+	}
+
+	assertSameValue(t, len(records), 3)
+	assertSameValue(t, records[0].Level, "DEBUG")
+	assertSameValue(t, records[0].Msg, "simpledi: instance created")
+	assertSameValue(t, records[0].ID, "yeast")
+	assertSameValue(t, records[1].ID, "bread")
+	assertOrder(t, records[1].Deps, []string{"yeast"})
+	assertSameValue(t, records[2].Level, "ERROR")
+	assertSameValue(t, records[2].Msg, "simpledi: instance closed")
+	assertSameValue(t, records[2].Error, someError.Error())
+}
+
+func Test_Logger_Level_Filtered(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	c := simpledi.New(simpledi.WithLogger(logger, slog.LevelDebug))
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertNoError(t, c.Close)
+
+	assertSameValue(t, buf.Len(), 0)
+}
+
+func Test_Logger_Deps_Match_Graph(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	c := simpledi.New(simpledi.WithLogger(logger, slog.LevelInfo))
+
+	for _, id := range []string{"yeast", "salt", "butter"} {
+		id := id
+		assertNoError(t, func() error {
+			return c.Set(simpledi.Definition{
+				ID: id,
+				New: func() any {
+					return id
+				},
+			})
+		})
+	}
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:       "bread",
+			Deps:     []string{"yeast"},
+			Optional: []string{"salt", "sugar"},
+			New: func() any {
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Decorate("bread", func(instance any) any { return instance }, "butter")
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	var deps []string
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var r struct {
+			ID   string   `json:"id"`
+			Deps []string `json:"deps"`
+		}
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("got: %v, want: no error", err)
+		}
+		if r.ID == "bread" {
+			deps = r.Deps
+		}
+	}
+	assertOrder(t, deps, c.Graph()["bread"])
+	assertOrder(t, deps, []string{"yeast", "salt", "butter"})
+}
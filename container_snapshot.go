@@ -0,0 +1,197 @@
+package simpledi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Snapshot is a saved state of a container: its definitions and instances.
+type Snapshot struct {
+	epoch       uint64
+	resolved    bool
+	definitions []Definition
+	instances   map[string]any
+	builds      map[string]uint64
+	generations map[string]uint64
+	decorators  map[string][]decorator
+	overridden  []Definition
+	ignored     []Definition
+}
+
+type retiredInstance struct {
+	definition Definition
+	instance   any
+	build      uint64
+	generation uint64
+}
+
+// Snapshot saves the current state of the container.
+// Instances captured by a snapshot are not closed when they are replaced,
+// so Restore can bring them back without calling New again.
+// They are closed by Close.
+func (c *Container) Snapshot() *Snapshot {
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, build := range c.builds {
+		c.pinned[build] = true
+	}
+
+	decorators := make(map[string][]decorator, len(c.decorators))
+	for id, d := range c.decorators {
+		decorators[id] = slices.Clip(d)
+	}
+
+	return &Snapshot{
+		epoch:       c.epoch,
+		resolved:    c.resolved,
+		definitions: slices.Clone(c.definitions),
+		instances:   maps.Clone(c.instances),
+		builds:      maps.Clone(c.builds),
+		generations: maps.Clone(c.generations),
+		decorators:  decorators,
+		overridden:  slices.Clone(c.overridden),
+		ignored:     slices.Clone(c.ignored),
+	}
+}
+
+// Restore returns the container to the state saved by Snapshot.
+// Instances created after the snapshot are closed in reverse order,
+// instances captured by the snapshot are reused as is.
+// Watchers get EventRebuilt for every instance brought back and EventClosed for every instance dropped.
+// Generations never go back: an instance brought back gets a new generation.
+// Async definitions still running New for an instance that is dropped are cancelled and waited for first.
+// Returns a combined error if any Close calls fail.
+func (c *Container) Restore(s *Snapshot) error {
+	const op = "simpledi.Restore"
+
+	c.rebuildMu.Lock()
+	defer c.rebuildMu.Unlock()
+
+	if s.epoch != c.epoch && len(s.instances) > 0 {
+		return fmt.Errorf("%s: %w", op, ErrSnapshotStale)
+	}
+
+	c.mu.RLock()
+	builds := maps.Clone(c.builds)
+	generations := maps.Clone(c.generations)
+	c.mu.RUnlock()
+
+	dropped := make([]Definition, 0)
+	for i := len(c.definitions) - 1; i >= 0; i-- {
+		definition := c.definitions[i]
+		if build, ok := builds[definition.ID]; ok && s.builds[definition.ID] != build {
+			dropped = append(dropped, definition)
+		}
+	}
+	pending := make([]*Future, 0)
+	for _, a := range c.futures() {
+		if a.future != nil && builds[a.id] != s.builds[a.id] {
+			a.future.cancel()
+			pending = append(pending, a.future)
+		}
+	}
+	for _, future := range pending {
+		<-future.done
+	}
+
+	errs := make([]error, 0)
+	events := make([]Event, 0)
+	ctx, end := c.traceTask(context.Background(), op)
+	for _, definition := range dropped {
+		if _, ok := s.instances[definition.ID]; !ok {
+			events = append(events, Event{ID: definition.ID, Kind: EventClosed, Generation: generations[definition.ID]})
+		}
+		if err := c.discard(ctx, definition); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w (%s)", op, err, definition.label()))
+		}
+	}
+	end()
+
+	retired := make([]retiredInstance, 0, len(c.retired))
+	for _, r := range c.retired {
+		if s.builds[r.definition.ID] != r.build {
+			retired = append(retired, r)
+		}
+	}
+
+	decorators := make(map[string][]decorator, len(s.decorators))
+	for id, d := range s.decorators {
+		decorators[id] = slices.Clip(d)
+	}
+
+	c.retired = retired
+	c.resolved = s.resolved
+	c.definitions = slices.Clone(s.definitions)
+	c.mu.Lock()
+	c.instances = maps.Clone(s.instances)
+	c.builds = maps.Clone(s.builds)
+	c.generations = make(map[string]uint64, len(s.instances))
+	for _, definition := range c.definitions {
+		if _, ok := s.instances[definition.ID]; !ok {
+			continue
+		}
+		if builds[definition.ID] == s.builds[definition.ID] {
+			c.generations[definition.ID] = generations[definition.ID]
+			continue
+		}
+		c.generations[definition.ID] = max(generations[definition.ID], s.generations[definition.ID]) + 1
+		events = append(events, Event{ID: definition.ID, Kind: EventRebuilt, Generation: c.generations[definition.ID]})
+	}
+	c.mu.Unlock()
+	c.decorators = decorators
+	c.overridden = slices.Clone(s.overridden)
+	c.ignored = slices.Clone(s.ignored)
+	for _, event := range events {
+		c.notify(event)
+	}
+
+	return errors.Join(errs...)
+}
+
+// discard closes the current instance of the definition and removes it,
+// or keeps it aside if it is captured by a snapshot.
+func (c *Container) discard(ctx context.Context, definition Definition) error {
+	c.mu.RLock()
+	retired := retiredInstance{
+		definition: definition,
+		instance:   c.instances[definition.ID],
+		build:      c.builds[definition.ID],
+		generation: c.generations[definition.ID],
+	}
+	c.mu.RUnlock()
+
+	var err error
+	if c.pinned[retired.build] {
+		c.retired = append(c.retired, retired)
+	} else if definition.Close != nil {
+		err = c.closeInstance(ctx, definition)
+	}
+	c.remove(definition.ID)
+
+	return err
+}
+
+// closeSuperseded calls Close of the retired definition while Get returns its instance for its ID.
+func (c *Container) closeSuperseded(ctx context.Context, retired retiredInstance) error {
+	id := retired.definition.ID
+	c.mu.Lock()
+	if c.closing == nil {
+		c.closing = make(map[string]retiredInstance)
+	}
+	c.closing[id] = retired
+	c.mu.Unlock()
+
+	err := c.closeInstance(ctx, retired.definition)
+
+	c.mu.Lock()
+	delete(c.closing, id)
+	c.mu.Unlock()
+
+	return err
+}
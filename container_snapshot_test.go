@@ -0,0 +1,194 @@
+package simpledi_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Snapshot_Restore_After_Override(t *testing.T) {
+	c := simpledi.New()
+	calls := make(map[string]int)
+	closed := make([]string, 0)
+	definition := func(id string, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				calls[id]++
+				return &ServiceImplB{data: id}
+			},
+			Close: func() error {
+				instance, _ := c.Get(id)
+				closed = append(closed, instance.(*ServiceImplB).data)
+				return nil
+			},
+		}
+	}
+
+	for _, d := range []simpledi.Definition{
+		definition("config"),
+		definition("database", "config"),
+		definition("service", "database"),
+	} {
+		assertNoError(t, func() error { return c.Set(d) })
+	}
+	assertNoError(t, c.Resolve)
+	database, _ := c.Get("database")
+	service, _ := c.Get("service")
+	snapshot := c.Snapshot()
+
+	assertNoError(t, func() error {
+		return c.Override(simpledi.Definition{
+			ID:   "database",
+			Deps: []string{"config"},
+			New: func() any {
+				return &ServiceImplB{data: "fake"}
+			},
+			Close: func() error {
+				instance, _ := c.Get("database")
+				closed = append(closed, instance.(*ServiceImplB).data)
+				return nil
+			},
+		})
+	})
+	assertSameValue(t, len(closed), 0)
+	assertSameValue(t, calls["service"], 2)
+
+	assertNoError(t, func() error { return c.Restore(snapshot) })
+	assertOrder(t, closed, []string{"service", "fake"})
+
+	restoredDatabase, _ := c.Get("database")
+	restoredService, _ := c.Get("service")
+	assertSameValue(t, restoredDatabase, database)
+	assertSameValue(t, restoredService, service)
+	assertSameValue(t, calls["config"], 1)
+	assertSameValue(t, calls["database"], 1)
+	assertSameValue(t, calls["service"], 2)
+	assertSameValue(t, len(c.Overridden()), 0)
+
+	closed = closed[:0]
+	assertNoError(t, c.Close)
+	assertOrder(t, closed, []string{"service", "database", "config"})
+}
+
+func Test_Snapshot_Close_Closes_Retired(t *testing.T) {
+	c := simpledi.New()
+	closed := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+			Close: func() error {
+				instance, _ := c.Get("database")
+				closed = append(closed, instance.(string))
+				return nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	_ = c.Snapshot()
+
+	assertNoError(t, func() error {
+		return c.Override(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "fake"
+			},
+		})
+	})
+	assertNoError(t, c.Close)
+
+	assertOrder(t, closed, []string{"postgres"})
+}
+
+func Test_Snapshot_Unresolved(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	snapshot := c.Snapshot()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "cache",
+			New: func() any {
+				return "redis"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertNoError(t, func() error { return c.Restore(snapshot) })
+	assertError(t, func() error {
+		_, err := c.Get("cache")
+		return err
+	}, simpledi.ErrIDNotFound)
+
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	assertError(t, func() error {
+		_, err := c.Get("cache")
+		return err
+	}, simpledi.ErrIDNotFound)
+}
+
+func Test_Snapshot_Err_Stale(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	snapshot := c.Snapshot()
+	assertNoError(t, c.Close)
+
+	assertError(t, func() error { return c.Restore(snapshot) }, simpledi.ErrSnapshotStale)
+}
+
+func Test_Snapshot_Concurrent(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:         "credentials",
+			Reloadable: true,
+			New: func() any {
+				return "credentials"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Snapshot()
+		}()
+		go func() {
+			defer wg.Done()
+			_ = c.Reload()
+		}()
+	}
+	wg.Wait()
+
+	credentials, _ := c.Get("credentials")
+	assertSameValue(t, credentials, any("credentials"))
+}
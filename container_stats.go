@@ -0,0 +1,177 @@
+package simpledi
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// Stats describes how long the container spent creating and closing instances.
+type Stats struct {
+	// Resolve is the total wall time of the last Resolve.
+	Resolve time.Duration
+	// Close is the total wall time of the last Close.
+	Close time.Duration
+	// Definitions holds per-definition timings in resolution order.
+	Definitions []DefinitionStats
+	// CriticalPath is the dependency chain with the longest total New duration,
+	// from the first definition to the last.
+	CriticalPath []string
+	// CriticalPathDuration is the total New duration of CriticalPath.
+	CriticalPathDuration time.Duration
+}
+
+// DefinitionStats describes timings of a single definition.
+type DefinitionStats struct {
+	// ID is the identifier of the definition.
+	ID string
+	// Deps is the list of dependency IDs of the definition.
+	Deps []string
+	// New is the time spent in New.
+	New time.Duration
+	// Close is the time spent in Close.
+	Close time.Duration
+}
+
+// Stats returns timings recorded by the last Resolve and Close.
+func (c *Container) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// Publish exposes the container stats through expvar under the given name.
+// Like expvar.Publish, it panics if the name is already registered.
+func (c *Container) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return c.Stats()
+	}))
+}
+
+type stats struct {
+	mu          sync.Mutex
+	resolve     time.Duration
+	close       time.Duration
+	definitions []DefinitionStats
+	index       map[string]int
+}
+
+func (s *stats) reset(definitions []Definition, deps func(Definition) []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resolve = 0
+	s.close = 0
+	s.definitions = make([]DefinitionStats, len(definitions))
+	s.index = make(map[string]int, len(definitions))
+	for i, definition := range definitions {
+		s.index[definition.ID] = i
+		s.definitions[i] = DefinitionStats{
+			ID:   definition.ID,
+			Deps: deps(definition),
+		}
+	}
+}
+
+func (s *stats) add(definitions []Definition, deps func(Definition) []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, definition := range definitions {
+		s.index[definition.ID] = len(s.definitions)
+		s.definitions = append(s.definitions, DefinitionStats{
+			ID:   definition.ID,
+			Deps: deps(definition),
+		})
+	}
+}
+
+// remove drops the stats of the definitions added last by add.
+func (s *stats) remove(definitions []Definition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, definition := range definitions {
+		delete(s.index, definition.ID)
+	}
+	s.definitions = s.definitions[:len(s.definitions)-len(definitions)]
+}
+
+func (s *stats) recordNew(id string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i, ok := s.index[id]; ok {
+		s.definitions[i].New = d
+	}
+}
+
+func (s *stats) recordClose(id string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i, ok := s.index[id]; ok {
+		s.definitions[i].Close = d
+	}
+}
+
+func (s *stats) recordResolve(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resolve = d
+}
+
+func (s *stats) recordCloseTotal(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.close = d
+}
+
+func (s *stats) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := Stats{
+		Resolve:     s.resolve,
+		Close:       s.close,
+		Definitions: make([]DefinitionStats, len(s.definitions)),
+	}
+	copy(result.Definitions, s.definitions)
+	result.CriticalPath, result.CriticalPathDuration = criticalPath(result.Definitions)
+
+	return result
+}
+
+// criticalPath expects definitions in topological order.
+func criticalPath(definitions []DefinitionStats) ([]string, time.Duration) {
+	if len(definitions) == 0 {
+		return nil, 0
+	}
+
+	finish := make(map[string]time.Duration, len(definitions))
+	prev := make(map[string]string, len(definitions))
+	last := ""
+	for _, definition := range definitions {
+		longest := time.Duration(0)
+		for _, dependency := range definition.Deps {
+			if d, ok := finish[dependency]; ok && (d > longest || prev[definition.ID] == "") {
+				longest = d
+				prev[definition.ID] = dependency
+			}
+		}
+		finish[definition.ID] = longest + definition.New
+		if last == "" || finish[definition.ID] > finish[last] {
+			last = definition.ID
+		}
+	}
+
+	path := make([]string, 0)
+	for id := last; id != ""; id = prev[id] {
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, finish[last]
+}
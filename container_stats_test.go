@@ -0,0 +1,101 @@
+package simpledi_test
+
+import (
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Stats_Records_New_And_Close(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				time.Sleep(time.Millisecond)
+				return "yeast"
+			},
+			Close: func() error {
+				time.Sleep(time.Millisecond)
+				return nil
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "bread",
+			Deps: []string{"yeast"},
+			New: func() any {
+				return "bread"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertNoError(t, c.Close)
+
+	stats := c.Stats()
+	assertSameValue(t, len(stats.Definitions), 2)
+	assertSameValue(t, stats.Definitions[0].ID, "yeast")
+	assertSameValue(t, stats.Definitions[1].ID, "bread")
+	assertSameValue(t, stats.Definitions[0].New >= time.Millisecond, true)
+	assertSameValue(t, stats.Definitions[0].Close >= time.Millisecond, true)
+	assertSameValue(t, stats.Definitions[1].Close, 0)
+	assertSameValue(t, stats.Resolve >= stats.Definitions[0].New, true)
+	assertSameValue(t, stats.Close >= stats.Definitions[0].Close, true)
+}
+
+func Test_Stats_Critical_Path(t *testing.T) {
+	c := simpledi.New()
+	sleep := func(id string, d time.Duration, deps ...string) simpledi.Definition {
+		return simpledi.Definition{
+			ID:   id,
+			Deps: deps,
+			New: func() any {
+				time.Sleep(d)
+				return id
+			},
+		}
+	}
+
+	for _, d := range []simpledi.Definition{
+		sleep("config", time.Millisecond),
+		sleep("cache", time.Millisecond, "config"),
+		sleep("database", 10*time.Millisecond, "config"),
+		sleep("service", time.Millisecond, "cache", "database"),
+	} {
+		assertNoError(t, func() error { return c.Set(d) })
+	}
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	stats := c.Stats()
+	assertOrder(t, stats.CriticalPath, []string{"config", "database", "service"})
+	assertSameValue(t, stats.CriticalPathDuration >= 12*time.Millisecond, true)
+}
+
+func Test_Stats_Empty(t *testing.T) {
+	c := simpledi.New()
+
+	stats := c.Stats()
+	assertSameValue(t, len(stats.Definitions), 0)
+	assertSameValue(t, len(stats.CriticalPath), 0)
+}
+
+func Test_Stats_Publish(t *testing.T) {
+	c := simpledi.New()
+	name := "simpledi_test_stats"
+	for i := 1; expvar.Get(name) != nil; i++ {
+		name = fmt.Sprintf("simpledi_test_stats_%d", i)
+	}
+	c.Publish(name)
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("got: nil, want: published var")
+	}
+	assertSameValue(t, v.String() != "", true)
+}
@@ -0,0 +1,37 @@
+package simpledi
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// TraceLabel is the pprof label key holding the definition ID.
+const TraceLabel = "simpledi.id"
+
+// WithTrace wraps Resolve and Close in a runtime/trace task and every New and Close call
+// in a runtime/trace region, with the pprof label TraceLabel set to the definition ID.
+func WithTrace() Option {
+	return func(c *Container) {
+		c.trace = true
+	}
+}
+
+func (c *Container) traceTask(ctx context.Context, name string) (context.Context, func()) {
+	if !c.trace {
+		return ctx, func() {}
+	}
+	ctx, task := trace.NewTask(ctx, name)
+
+	return ctx, task.End
+}
+
+func (c *Container) traceRegion(ctx context.Context, name, id string, fn func()) {
+	if !c.trace {
+		fn()
+		return
+	}
+	pprof.Do(ctx, pprof.Labels(TraceLabel, id), func(ctx context.Context) {
+		trace.WithRegion(ctx, name+" "+id, fn)
+	})
+}
@@ -0,0 +1,67 @@
+package simpledi_test
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Trace_Regions(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("trace already running: %v", err)
+	}
+	c := simpledi.New(simpledi.WithTrace())
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+			Close: func() error {
+				return nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertNoError(t, c.Close)
+	trace.Stop()
+
+	for _, want := range []string{"simpledi.Resolve", "simpledi.Close", "simpledi.New yeast", "simpledi.Close yeast"} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("got: no %q in trace, want: %q", want, want)
+		}
+	}
+}
+
+func Test_Trace_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("trace already running: %v", err)
+	}
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "yeast",
+			New: func() any {
+				return "yeast"
+			},
+			Close: func() error {
+				return nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	assertNoError(t, c.Close)
+	trace.Stop()
+
+	for _, unwanted := range []string{"simpledi.Resolve", "simpledi.Close", "simpledi.New yeast", "simpledi.Close yeast"} {
+		if bytes.Contains(buf.Bytes(), []byte(unwanted)) {
+			t.Errorf("got: %q in trace, want: no %q", unwanted, unwanted)
+		}
+	}
+}
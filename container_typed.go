@@ -0,0 +1,35 @@
+package simpledi
+
+import "fmt"
+
+// GetFrom returns an instance of type T by ID from the given container.
+// The error wraps ErrIDNotFound or ErrTypeMismatch.
+func GetFrom[T any](c *Container, id string) (T, error) {
+	const op = "simpledi.Get"
+
+	var zero T
+	instance, err := c.Get(id)
+	if err != nil {
+		return zero, err
+	}
+	if instance == nil {
+		return zero, nil
+	}
+	typedInstance, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s: %w (ID: %s, Want: %T, Got: %T)", op, ErrTypeMismatch, id, zero, instance)
+	}
+
+	return typedInstance, nil
+}
+
+// LookupFrom returns an instance of type T by ID from the given container.
+// The boolean is false if the ID is not found or the instance has another type.
+func LookupFrom[T any](c *Container, id string) (T, bool) {
+	instance, err := GetFrom[T](c, id)
+	if err != nil {
+		return instance, false
+	}
+
+	return instance, true
+}
@@ -0,0 +1,99 @@
+package simpledi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_GetFrom(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "service_1",
+			New: func() any {
+				return &ServiceImplA{}
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "nil_val",
+			New: func() any {
+				return nil
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	service, err := simpledi.GetFrom[ServiceA](c, "service_1")
+	assertNoError(t, func() error { return err })
+	if service == nil {
+		t.Errorf("got: nil, want: instance")
+	}
+
+	nilVal, err := simpledi.GetFrom[*ServiceImplA](c, "nil_val")
+	assertNoError(t, func() error { return err })
+	assertSamePointer(t, nilVal, nil)
+
+	_, err = simpledi.GetFrom[*ServiceImplB](c, "service_1")
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+
+	_, err = simpledi.GetFrom[*ServiceImplA](c, "not_found")
+	assertError(t, func() error { return err }, simpledi.ErrIDNotFound)
+}
+
+func Test_LookupFrom(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "service_1",
+			New: func() any {
+				return &ServiceImplA{}
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	_, ok := simpledi.LookupFrom[*ServiceImplA](c, "service_1")
+	assertSameValue(t, ok, true)
+	_, ok = simpledi.LookupFrom[*ServiceImplB](c, "service_1")
+	assertSameValue(t, ok, false)
+	_, ok = simpledi.LookupFrom[*ServiceImplA](c, "not_found")
+	assertSameValue(t, ok, false)
+}
+
+func Test_TryGet_And_Lookup(t *testing.T) {
+	defer simpledi.Close()
+
+	assertNoPanic(t, func() {
+		simpledi.Set(simpledi.Definition{
+			ID: "service_2",
+			New: func() any {
+				return &ServiceImplB{data: "data"}
+			},
+		})
+		simpledi.Resolve()
+	})
+
+	service, err := simpledi.TryGet[*ServiceImplB]("service_2")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, service.data, "data")
+
+	_, err = simpledi.TryGet[*ServiceImplA]("service_2")
+	if !errors.Is(err, simpledi.ErrTypeMismatch) {
+		t.Errorf("got: %v, want: %v", err, simpledi.ErrTypeMismatch)
+	}
+	_, err = simpledi.TryGet[*ServiceImplB]("")
+	assertError(t, func() error { return err }, simpledi.ErrIDRequired)
+
+	_, ok := simpledi.Lookup[*ServiceImplB]("service_2")
+	assertSameValue(t, ok, true)
+	_, ok = simpledi.Lookup[*ServiceImplB]("not_found")
+	assertSameValue(t, ok, false)
+}
@@ -0,0 +1,135 @@
+package simpledi
+
+import (
+	"fmt"
+	"slices"
+)
+
+// EventKind is the kind of change reported to Watch subscribers.
+type EventKind int
+
+const (
+	// EventRebuilt reports that another instance was swapped in by Replace, Override, Reload or Restore.
+	EventRebuilt EventKind = iota + 1
+	// EventClosed reports that the instance was closed by Close or dropped by Restore.
+	EventClosed
+)
+
+// String returns the name of the kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventRebuilt:
+		return "rebuilt"
+	case EventClosed:
+		return "closed"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is a change of the instance with the given ID.
+type Event struct {
+	ID   string
+	Kind EventKind
+	// Generation is the generation of the instance: of the new one for EventRebuilt,
+	// of the closed one for EventClosed.
+	Generation uint64
+}
+
+// Watch calls fn on every change of the instance with the given ID.
+// Subscribers are called synchronously in subscription order,
+// after a new instance is swapped in and before the old one is closed.
+// The returned function cancels the subscription.
+func (c *Container) Watch(id string, fn func(Event)) (cancel func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.watchers == nil {
+		c.watchers = make(map[string]map[uint64]func(Event))
+	}
+	if c.watchers[id] == nil {
+		c.watchers[id] = make(map[uint64]func(Event))
+	}
+	c.watchSerial++
+	key := c.watchSerial
+	c.watchers[id][key] = fn
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.watchers[id], key)
+		if len(c.watchers[id]) == 0 {
+			delete(c.watchers, id)
+		}
+	}
+}
+
+// Generation returns the generation of the instance with the given ID.
+// It starts at 1 when the instance is created and grows every time another instance is swapped in,
+// including by Restore.
+// Returns 0 if the instance is not created.
+func (c *Container) Generation(id string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.generations[id]
+}
+
+// GetVersioned returns an instance by ID along with its generation.
+// It sees the same instance as Get, including during Replace.
+func (c *Container) GetVersioned(id string) (any, uint64, error) {
+	const op = "simpledi.GetVersioned"
+
+	if id == "" {
+		return nil, 0, fmt.Errorf("%s: %w", op, ErrIDRequired)
+	}
+	c.onGet(id)
+	instance, generation, ok := c.lookupVersioned(id)
+	if !ok {
+		return nil, 0, fmt.Errorf("%s: %w (ID: %s)", op, ErrIDNotFound, id)
+	}
+
+	return instance, generation, nil
+}
+
+// GetVersionedFrom returns an instance of type T by ID from the given container along with its generation.
+// The error wraps ErrIDNotFound or ErrTypeMismatch.
+func GetVersionedFrom[T any](c *Container, id string) (T, uint64, error) {
+	const op = "simpledi.GetVersioned"
+
+	var zero T
+	instance, generation, err := c.GetVersioned(id)
+	if err != nil {
+		return zero, 0, err
+	}
+	if instance == nil {
+		return zero, generation, nil
+	}
+	typedInstance, ok := instance.(T)
+	if !ok {
+		return zero, 0, fmt.Errorf("%s: %w (ID: %s, Want: %T, Got: %T)", op, ErrTypeMismatch, id, zero, instance)
+	}
+
+	return typedInstance, generation, nil
+}
+
+func (c *Container) notify(event Event) {
+	c.mu.RLock()
+	keys := make([]uint64, 0, len(c.watchers[event.ID]))
+	for key := range c.watchers[event.ID] {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	fns := make([]func(Event), 0, len(keys))
+	for _, key := range keys {
+		fns = append(fns, c.watchers[event.ID][key])
+	}
+	c.mu.RUnlock()
+
+	for _, fn := range fns {
+		if fn != nil {
+			fn(event)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package simpledi_test
+
+import (
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+func Test_Watch_Events(t *testing.T) {
+	c := simpledi.New()
+	events := make([]simpledi.Event, 0)
+	version := 0
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:         "credentials",
+			Reloadable: true,
+			New: func() any {
+				version++
+				return version
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "database",
+			Deps: []string{"credentials"},
+			New: func() any {
+				return "database"
+			},
+		})
+	})
+	cancel := c.Watch("database", func(e simpledi.Event) {
+		events = append(events, e)
+	})
+	assertNoError(t, c.Resolve)
+	assertSameValue(t, c.Generation("database"), uint64(1))
+
+	assertNoError(t, c.Reload)
+	assertNoError(t, c.Reload)
+	assertNoError(t, c.Close)
+	assertSameValue(t, c.Generation("database"), uint64(0))
+
+	assertSameValue(t, len(events), 3)
+	assertSameValue(t, events[0], simpledi.Event{ID: "database", Kind: simpledi.EventRebuilt, Generation: 2})
+	assertSameValue(t, events[1], simpledi.Event{ID: "database", Kind: simpledi.EventRebuilt, Generation: 3})
+	assertSameValue(t, events[2], simpledi.Event{ID: "database", Kind: simpledi.EventClosed, Generation: 3})
+
+	cancel()
+	assertNoError(t, c.Resolve)
+	assertNoError(t, c.Reload)
+	assertNoError(t, c.Close)
+	assertSameValue(t, len(events), 3)
+}
+
+func Test_Watch_Before_Old_Close(t *testing.T) {
+	c := simpledi.New()
+	order := make([]string, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+			Close: func() error {
+				order = append(order, "close")
+				return nil
+			},
+		})
+	})
+	c.Watch("database", func(e simpledi.Event) {
+		order = append(order, e.Kind.String())
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+
+	assertNoError(t, func() error {
+		return c.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "sqlite"
+			},
+		})
+	})
+	assertOrder(t, order, []string{"rebuilt", "close"})
+}
+
+func Test_GetVersioned(t *testing.T) {
+	c := simpledi.New()
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	assertNoError(t, func() error {
+		return c.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "sqlite"
+			},
+		})
+	})
+
+	database, generation, err := simpledi.GetVersionedFrom[string](c, "database")
+	assertNoError(t, func() error { return err })
+	assertSameValue(t, database, "sqlite")
+	assertSameValue(t, generation, uint64(2))
+
+	_, _, err = simpledi.GetVersionedFrom[int](c, "database")
+	assertError(t, func() error { return err }, simpledi.ErrTypeMismatch)
+	_, _, err = c.GetVersioned("cache")
+	assertError(t, func() error { return err }, simpledi.ErrIDNotFound)
+}
+
+func Test_GetVersioned_During_Replace(t *testing.T) {
+	c := simpledi.New()
+	seen := make([]any, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+			Close: func() error {
+				database, generation, _ := c.GetVersioned("database")
+				seen = append(seen, database, generation)
+				return nil
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID:   "repository",
+			Deps: []string{"database"},
+			New: func() any {
+				database, generation, _ := c.GetVersioned("database")
+				seen = append(seen, database, generation)
+				return "repository"
+			},
+		})
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	assertNoError(t, func() error {
+		return c.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "sqlite"
+			},
+		})
+	})
+
+	assertOrder(t, seen, []any{"postgres", uint64(1), "sqlite", uint64(2), "postgres", uint64(1)})
+}
+
+func Test_Watch_Restore(t *testing.T) {
+	c := simpledi.New()
+	events := make([]simpledi.Event, 0)
+
+	assertNoError(t, func() error {
+		return c.Set(simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return "postgres"
+			},
+		})
+	})
+	c.Watch("database", func(e simpledi.Event) {
+		events = append(events, e)
+	})
+	c.Watch("cache", func(e simpledi.Event) {
+		events = append(events, e)
+	})
+	assertNoError(t, c.Resolve)
+	defer c.Close()
+	snapshot := c.Snapshot()
+
+	assertNoError(t, func() error {
+		return c.Replace("database", simpledi.Definition{
+			New: func() any {
+				return "sqlite"
+			},
+		})
+	})
+	assertNoError(t, func() error {
+		return c.Extend(simpledi.Definition{
+			ID: "cache",
+			New: func() any {
+				return "redis"
+			},
+		})
+	})
+	assertNoError(t, func() error { return c.Restore(snapshot) })
+
+	assertSameValue(t, len(events), 3)
+	assertSameValue(t, events[0], simpledi.Event{ID: "database", Kind: simpledi.EventRebuilt, Generation: 2})
+	assertSameValue(t, events[1], simpledi.Event{ID: "cache", Kind: simpledi.EventClosed, Generation: 1})
+	assertSameValue(t, events[2], simpledi.Event{ID: "database", Kind: simpledi.EventRebuilt, Generation: 3})
+
+	database, generation, _ := c.GetVersioned("database")
+	assertSameValue(t, database, any("postgres"))
+	assertSameValue(t, generation, uint64(3))
+	assertSameValue(t, c.Generation("cache"), uint64(0))
+}
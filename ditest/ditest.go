@@ -0,0 +1,126 @@
+// Package ditest provides helpers for using simpledi containers in tests.
+//
+// Every helper reports failures through testing.TB instead of returning errors or panicking.
+package ditest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+)
+
+// New returns an isolated container that is closed when the test finishes.
+// The test fails if Close returns an error.
+func New(t testing.TB, opts ...simpledi.Option) *simpledi.Container {
+	t.Helper()
+
+	c := simpledi.New(opts...)
+	closeOnCleanup(t, c, "ditest.New")
+
+	return c
+}
+
+// Set adds a definition to the container.
+func Set(t testing.TB, c *simpledi.Container, d simpledi.Definition) {
+	t.Helper()
+
+	if err := c.Set(d); err != nil {
+		t.Fatalf("ditest.Set: %v", err)
+	}
+}
+
+// Override replaces an existing definition with the same ID.
+func Override(t testing.TB, c *simpledi.Container, d simpledi.Definition) {
+	t.Helper()
+
+	if err := c.Override(d); err != nil {
+		t.Fatalf("ditest.Override: %v", err)
+	}
+}
+
+// Fake replaces an existing definition with one that returns the given instance.
+func Fake(t testing.TB, c *simpledi.Container, id string, instance any) {
+	t.Helper()
+
+	err := c.Override(simpledi.Definition{
+		ID: id,
+		New: func() any {
+			return instance
+		},
+	})
+	if err != nil {
+		t.Fatalf("ditest.Fake: %v", err)
+	}
+}
+
+// Resolve creates instances for all registered definitions.
+func Resolve(t testing.TB, c *simpledi.Container) {
+	t.Helper()
+
+	if err := c.Resolve(); err != nil {
+		t.Fatalf("ditest.Resolve: %v", err)
+	}
+}
+
+// MustGet returns an instance by ID.
+// The test fails immediately if the ID is missing or the instance has another type.
+func MustGet[T any](t testing.TB, c *simpledi.Container, id string) T {
+	t.Helper()
+
+	instance, err := simpledi.GetFrom[T](c, id)
+	if err != nil {
+		t.Fatalf("ditest.MustGet[%s] (ID: %s): %v", fmt.Sprintf("%T", (*T)(nil))[1:], id, err)
+	}
+
+	return instance
+}
+
+var defaultMu sync.Mutex
+
+// Default installs an isolated container as the simpledi default container for the test
+// and restores the previous one when the test finishes.
+//
+// A test that is not parallel never runs along with other tests, so Default marks it as such
+// the way t.Setenv does: calling t.Parallel after Default panics.
+// Parallel tests must call t.Parallel before Default; they are then run one at a time
+// while they hold the default container, so Default must not be called again from a subtest
+// of a parallel test that holds it.
+func Default(t testing.TB, opts ...simpledi.Option) *simpledi.Container {
+	t.Helper()
+
+	if parallel(t) {
+		defaultMu.Lock()
+		t.Cleanup(defaultMu.Unlock)
+	}
+	c := simpledi.New(opts...)
+	previous := simpledi.SetDefault(c)
+	t.Cleanup(func() {
+		simpledi.SetDefault(previous)
+	})
+	closeOnCleanup(t, c, "ditest.Default")
+
+	return c
+}
+
+// parallel reports whether the test or any of its parents is parallel.
+// Otherwise the test can no longer call t.Parallel, as after t.Setenv.
+func parallel(t testing.TB) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = true
+		}
+	}()
+	t.Setenv("SIMPLEDI_DITEST_DEFAULT", t.Name())
+
+	return false
+}
+
+func closeOnCleanup(t testing.TB, c *simpledi.Container, op string) {
+	t.Cleanup(func() {
+		if err := c.Close(); err != nil {
+			t.Errorf("%s: close: %v", op, err)
+		}
+	})
+}
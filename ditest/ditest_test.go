@@ -0,0 +1,277 @@
+package ditest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/eerzho/simpledi"
+	"github.com/eerzho/simpledi/ditest"
+)
+
+type Database struct{ URL string }
+
+func Test_New_Closes_On_Cleanup(t *testing.T) {
+	closed := false
+
+	t.Run("sub", func(t *testing.T) {
+		c := ditest.New(t)
+		ditest.Set(t, c, simpledi.Definition{
+			ID: "database",
+			New: func() any {
+				return &Database{URL: "postgres"}
+			},
+			Close: func() error {
+				closed = true
+				return nil
+			},
+		})
+		ditest.Resolve(t, c)
+	})
+
+	if !closed {
+		t.Errorf("got: not closed, want: closed")
+	}
+}
+
+func Test_New_Close_Error_Fails_Test(t *testing.T) {
+	tb := &recorder{TB: t}
+	someError := errors.New("some error")
+
+	c := ditest.New(tb)
+	ditest.Set(tb, c, simpledi.Definition{
+		ID: "database",
+		New: func() any {
+			return &Database{}
+		},
+		Close: func() error {
+			return someError
+		},
+	})
+	ditest.Resolve(tb, c)
+	tb.cleanup()
+
+	if !strings.Contains(tb.errors, someError.Error()) {
+		t.Errorf("got: %q, want: %q", tb.errors, someError)
+	}
+}
+
+func Test_Fake(t *testing.T) {
+	c := ditest.New(t)
+	fake := &Database{URL: "fake"}
+
+	ditest.Set(t, c, simpledi.Definition{
+		ID: "database",
+		New: func() any {
+			return &Database{URL: "postgres"}
+		},
+	})
+	ditest.Fake(t, c, "database", fake)
+	ditest.Resolve(t, c)
+
+	got := ditest.MustGet[*Database](t, c, "database")
+	if got != fake {
+		t.Errorf("got: %p, want: %p", got, fake)
+	}
+}
+
+func Test_Override(t *testing.T) {
+	c := ditest.New(t)
+
+	ditest.Set(t, c, simpledi.Definition{
+		ID: "database",
+		New: func() any {
+			return &Database{URL: "postgres"}
+		},
+	})
+	ditest.Override(t, c, simpledi.Definition{
+		ID: "database",
+		New: func() any {
+			return &Database{URL: "sqlite"}
+		},
+	})
+	ditest.Resolve(t, c)
+
+	got := ditest.MustGet[*Database](t, c, "database")
+	if got.URL != "sqlite" {
+		t.Errorf("got: %s, want: sqlite", got.URL)
+	}
+}
+
+func Test_Fatal_Messages(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(tb testing.TB, c *simpledi.Container)
+		want string
+	}{
+		{
+			name: "set",
+			fn: func(tb testing.TB, c *simpledi.Container) {
+				ditest.Set(tb, c, simpledi.Definition{})
+			},
+			want: simpledi.ErrIDRequired.Error(),
+		},
+		{
+			name: "fake",
+			fn: func(tb testing.TB, c *simpledi.Container) {
+				ditest.Fake(tb, c, "cache", nil)
+			},
+			want: simpledi.ErrIDNotFound.Error(),
+		},
+		{
+			name: "resolve",
+			fn: func(tb testing.TB, c *simpledi.Container) {
+				ditest.Set(tb, c, simpledi.Definition{
+					ID:   "service",
+					Deps: []string{"database"},
+					New: func() any {
+						return nil
+					},
+				})
+				ditest.Resolve(tb, c)
+			},
+			want: simpledi.ErrDependencyNotFound.Error(),
+		},
+		{
+			name: "get not found",
+			fn: func(tb testing.TB, c *simpledi.Container) {
+				ditest.MustGet[*Database](tb, c, "database")
+			},
+			want: "ID: database",
+		},
+		{
+			name: "get type mismatch",
+			fn: func(tb testing.TB, c *simpledi.Container) {
+				ditest.Set(tb, c, simpledi.Definition{
+					ID: "database",
+					New: func() any {
+						return "postgres"
+					},
+				})
+				ditest.Resolve(tb, c)
+				ditest.MustGet[*Database](tb, c, "database")
+			},
+			want: "Want: *ditest_test.Database, Got: string",
+		},
+		{
+			name: "get interface",
+			fn: func(tb testing.TB, c *simpledi.Container) {
+				ditest.MustGet[io.Reader](tb, c, "reader")
+			},
+			want: "ditest.MustGet[io.Reader] (ID: reader): ",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tb := &recorder{TB: t}
+			c := simpledi.New()
+			defer c.Close()
+
+			tb.run(func() { tt.fn(tb, c) })
+
+			if !tb.failed {
+				t.Fatalf("got: no failure, want: %q", tt.want)
+			}
+			if !strings.Contains(tb.fatals, tt.want) {
+				t.Errorf("got: %q, want: %q", tb.fatals, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Default_Parallel(t *testing.T) {
+	for _, id := range []string{"a", "b", "c", "d"} {
+		id := id
+		t.Run(id, func(t *testing.T) {
+			t.Parallel()
+			ditest.Default(t)
+
+			simpledi.Set(simpledi.Definition{
+				ID: id,
+				New: func() any {
+					return id
+				},
+			})
+			simpledi.Resolve()
+
+			if got := simpledi.Get[string](id); got != id {
+				t.Errorf("got: %s, want: %s", got, id)
+			}
+		})
+	}
+}
+
+func Test_Default_Then_Parallel(t *testing.T) {
+	for _, id := range []string{"a", "b"} {
+		t.Run(id, func(t *testing.T) {
+			ditest.Default(t)
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("got: no panic, want: t.Parallel to panic after Default")
+				}
+			}()
+			t.Parallel()
+		})
+	}
+}
+
+func Test_Default_Restores_Previous(t *testing.T) {
+	previous := simpledi.Default()
+
+	t.Run("sub", func(t *testing.T) {
+		c := ditest.Default(t)
+		if simpledi.Default() != c {
+			t.Errorf("got: %p, want: %p", simpledi.Default(), c)
+		}
+	})
+
+	if simpledi.Default() != previous {
+		t.Errorf("got: %p, want: %p", simpledi.Default(), previous)
+	}
+}
+
+type recorder struct {
+	testing.TB
+	failed   bool
+	fatals   string
+	errors   string
+	cleanups []func()
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+	r.errors += fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failed = true
+	r.fatals += fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+func (r *recorder) run(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	<-done
+}
+
+func (r *recorder) cleanup() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}